@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"log/slog"
+	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/Sugyk/avito_test_task/internal/application"
+	"github.com/Sugyk/avito_test_task/internal/config"
+	"github.com/Sugyk/avito_test_task/pkg/database"
 )
 
 func main() {
@@ -16,14 +21,45 @@ func main() {
 	}
 }
 
+func migrateCommand(cfg *config.Config, args []string) (err error) {
+	m, err := database.NewMigrator(application.DatabaseConfig(cfg), application.MigrationsFS(cfg))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, m.Close())
+	}()
+	return runMigrate(m, args, os.Stdout)
+}
+
 func run() error {
+	cfg, cmd, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		log.Fatalln("can not load config:", err)
+	}
+	if cmd.PrintConfig {
+		return cfg.Print(os.Stdout)
+	}
+	if len(cmd.Args) > 0 {
+		if cmd.Args[0] != "migrate" {
+			log.Fatalf("unknown command %q", cmd.Args[0])
+		}
+		if err := migrateCommand(cfg, cmd.Args[1:]); err != nil {
+			log.Fatalln("migrate:", err)
+		}
+		return nil
+	}
+
 	ctx, cancel := signal.NotifyContext(
 		context.Background(),
 		syscall.SIGINT,
 		syscall.SIGTERM,
 	)
 
-	app := application.NewApplication()
+	app := application.NewApplication(cfg)
 	if err := app.Start(ctx); err != nil {
 		log.Fatalln("can not start application:", err)
 	}
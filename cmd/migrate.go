@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Sugyk/avito_test_task/pkg/database"
+)
+
+const migrateUsage = `Usage:
+  main [config flags] migrate <command>
+
+Commands:
+  up             apply all pending migrations
+  down N         roll back the last N migrations
+  goto VERSION   migrate up or down to VERSION
+  force VERSION  set VERSION and clear the dirty flag without running migrations, -1 for none
+  status         show applied and pending migrations
+`
+
+type migrator interface {
+	Up() error
+	Down(n int) error
+	Goto(version uint) error
+	Force(version int) error
+	Status() (database.MigrationStatus, error)
+}
+
+func runMigrate(m migrator, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+	command, args := args[0], args[1:]
+
+	arg := func() (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("migrate %s takes exactly one argument\n%s", command, migrateUsage)
+		}
+		return args[0], nil
+	}
+
+	var err error
+	switch command {
+	case "up":
+		err = m.Up()
+	case "down":
+		var raw string
+		if raw, err = arg(); err != nil {
+			return err
+		}
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n < 1 {
+			return fmt.Errorf("migrate down: invalid number of migrations %q", raw)
+		}
+		err = m.Down(n)
+	case "goto":
+		var raw string
+		if raw, err = arg(); err != nil {
+			return err
+		}
+		version, convErr := strconv.ParseUint(raw, 10, 0)
+		if convErr != nil {
+			return fmt.Errorf("migrate goto: invalid version %q", raw)
+		}
+		err = m.Goto(uint(version))
+	case "force":
+		var raw string
+		if raw, err = arg(); err != nil {
+			return err
+		}
+		version, convErr := strconv.Atoi(raw)
+		if convErr != nil || version < -1 {
+			return fmt.Errorf("migrate force: invalid version %q", raw)
+		}
+		err = m.Force(version)
+	case "status":
+		return printStatus(m, stdout)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+
+	if errors.Is(err, database.ErrNoChange) {
+		fmt.Fprintln(stdout, "no change")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(m, stdout)
+}
+
+func printStatus(m migrator, stdout io.Writer) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "version: %d\n", status.Version)
+	fmt.Fprintf(stdout, "dirty: %t\n", status.Dirty)
+	fmt.Fprintf(stdout, "latest: %d\n\n", status.Latest())
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATE")
+	for _, version := range status.Available {
+		state := "pending"
+		switch {
+		case version == status.Version && status.Dirty:
+			state = "dirty"
+		case version <= status.Version:
+			state = "applied"
+		}
+		fmt.Fprintf(tw, "%d\t%s\n", version, state)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/pkg/database"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMigrator struct {
+	status database.MigrationStatus
+	calls  []string
+	err    error
+}
+
+func (f *fakeMigrator) Up() error {
+	f.calls = append(f.calls, "up")
+	return f.err
+}
+
+func (f *fakeMigrator) Down(n int) error {
+	f.calls = append(f.calls, "down")
+	f.status.Version -= uint(n)
+	return f.err
+}
+
+func (f *fakeMigrator) Goto(version uint) error {
+	f.calls = append(f.calls, "goto")
+	f.status.Version = version
+	return f.err
+}
+
+func (f *fakeMigrator) Force(version int) error {
+	f.calls = append(f.calls, "force")
+	f.status.Version, f.status.Dirty = uint(version), false
+	return f.err
+}
+
+func (f *fakeMigrator) Status() (database.MigrationStatus, error) {
+	return f.status, nil
+}
+
+func TestRunMigrate(t *testing.T) {
+	m := &fakeMigrator{status: database.MigrationStatus{Version: 3, Available: []uint{1, 2, 3, 4}}}
+	var out bytes.Buffer
+
+	require.NoError(t, runMigrate(m, []string{"down", "2"}, &out))
+	require.Equal(t, uint(1), m.status.Version)
+
+	require.NoError(t, runMigrate(m, []string{"goto", "4"}, &out))
+	require.Equal(t, uint(4), m.status.Version)
+
+	m.status.Dirty = true
+	require.NoError(t, runMigrate(m, []string{"force", "3"}, &out))
+	require.False(t, m.status.Dirty)
+
+	require.Equal(t, []string{"down", "goto", "force"}, m.calls)
+}
+
+func TestRunMigrate_Status(t *testing.T) {
+	m := &fakeMigrator{status: database.MigrationStatus{Version: 2, Dirty: true, Available: []uint{1, 2, 3}}}
+	var out bytes.Buffer
+
+	require.NoError(t, runMigrate(m, []string{"status"}, &out))
+	require.Contains(t, out.String(), "version: 2\n")
+	require.Contains(t, out.String(), "latest: 3\n")
+	require.Regexp(t, `1\s+applied`, out.String())
+	require.Regexp(t, `2\s+dirty`, out.String())
+	require.Regexp(t, `3\s+pending`, out.String())
+}
+
+func TestRunMigrate_NoChange(t *testing.T) {
+	m := &fakeMigrator{err: database.ErrNoChange}
+	var out bytes.Buffer
+
+	require.NoError(t, runMigrate(m, []string{"up"}, &out))
+	require.Equal(t, "no change\n", out.String())
+}
+
+func TestRunMigrate_InvalidUsage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"sideways"},
+		{"down"},
+		{"down", "0"},
+		{"down", "1", "2"},
+		{"goto", "-1"},
+		{"force", "x"},
+	} {
+		m := &fakeMigrator{}
+		require.Error(t, runMigrate(m, args, &bytes.Buffer{}), args)
+		require.Empty(t, m.calls, args)
+	}
+}
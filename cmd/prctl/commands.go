@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Sugyk/avito_test_task/pkg/apitypes"
+)
+
+func (a *app) teamAdd(args []string) error {
+	fs := a.flagSet("team add")
+	file := fs.String("f", "", "path to the team JSON file, - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: -f is required", errUsage)
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	var team apitypes.Team
+	if err := json.NewDecoder(r).Decode(&team); err != nil {
+		return fmt.Errorf("decode team file: %w", err)
+	}
+	if err := team.Validate(); err != nil {
+		return fmt.Errorf("invalid team file: %w", err)
+	}
+
+	created, err := a.client.TeamAdd(a.ctx, &team)
+	if err != nil {
+		return err
+	}
+	return a.print(apitypes.TeamAddResponse201{Team: *created}, func(w *table) {
+		printTeam(w, created)
+	})
+}
+
+func (a *app) teamGet(args []string) error {
+	fs := a.flagSet("team get")
+	name := fs.String("name", "", "team name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	teamName, err := positional(fs, *name, "team name")
+	if err != nil {
+		return err
+	}
+
+	team, err := a.client.TeamGet(a.ctx, teamName)
+	if err != nil {
+		return err
+	}
+	return a.print(team, func(w *table) {
+		printTeam(w, team)
+	})
+}
+
+func (a *app) userSetIsActive(isActive bool) func([]string) error {
+	return func(args []string) error {
+		fs := a.flagSet("user")
+		id := fs.String("id", "", "user id")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		userID, err := positional(fs, *id, "user id")
+		if err != nil {
+			return err
+		}
+
+		user, err := a.client.UsersSetIsActive(a.ctx, userID, isActive)
+		if err != nil {
+			return err
+		}
+		return a.print(apitypes.UsersSerIsActiveResponse200{User: *user}, func(w *table) {
+			w.row("USER_ID", "USERNAME", "TEAM", "ACTIVE")
+			w.row(user.UserId, user.Username, user.TeamName, user.IsActive)
+		})
+	}
+}
+
+func (a *app) prCreate(args []string) error {
+	fs := a.flagSet("pr create")
+	var req apitypes.PullRequestCreateRequest
+	fs.StringVar(&req.PullRequestId, "id", "", "pull request id")
+	fs.StringVar(&req.PullRequestName, "name", "", "pull request name")
+	fs.StringVar(&req.AuthorId, "author", "", "author user id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+
+	pr, err := a.client.PullRequestCreate(a.ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(apitypes.PullRequestCreateResponse201{Pr: *pr}, func(w *table) {
+		printPullRequest(w, pr)
+	})
+}
+
+func (a *app) prMerge(args []string) error {
+	fs := a.flagSet("pr merge")
+	id := fs.String("id", "", "pull request id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	prID, err := positional(fs, *id, "pull request id")
+	if err != nil {
+		return err
+	}
+
+	pr, err := a.client.PullRequestMerge(a.ctx, prID)
+	if err != nil {
+		return err
+	}
+	return a.print(apitypes.PullRequestMergeResponse200{Pr: *pr}, func(w *table) {
+		printPullRequest(w, pr)
+	})
+}
+
+func (a *app) prReassign(args []string) error {
+	fs := a.flagSet("pr reassign")
+	var req apitypes.PullRequestReassignRequest
+	fs.StringVar(&req.PullRequestId, "id", "", "pull request id")
+	fs.StringVar(&req.OldReviewerId, "old-reviewer", "", "reviewer to replace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+
+	pr, replacedBy, err := a.client.PullRequestReassign(a.ctx, req.PullRequestId, req.OldReviewerId)
+	if err != nil {
+		return err
+	}
+	resp := apitypes.PullRequestReassignResponse200{Pr: *pr, ReplacedBy: replacedBy}
+	return a.print(resp, func(w *table) {
+		printPullRequest(w, pr)
+		w.flush()
+		fmt.Fprintf(a.stdout, "\nreplaced by: %s\n", replacedBy)
+	})
+}
+
+// prChangeReviewer runs a command adding or removing a reviewer with change.
+func (a *app) prChangeReviewer(name string, change func(ctx context.Context, prID, reviewerID string) (*apitypes.PullRequest, error)) func([]string) error {
+	return func(args []string) error {
+		fs := a.flagSet(name)
+		var req apitypes.PullRequestReviewerRequest
+		fs.StringVar(&req.PullRequestId, "id", "", "pull request id")
+		fs.StringVar(&req.ReviewerId, "reviewer", "", "reviewer user id")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if err := req.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", errUsage, err)
+		}
+
+		pr, err := change(a.ctx, req.PullRequestId, req.ReviewerId)
+		if err != nil {
+			return err
+		}
+		return a.print(apitypes.PullRequestReviewerResponse200{Pr: *pr}, func(w *table) {
+			printPullRequest(w, pr)
+		})
+	}
+}
+
+func (a *app) reviewList(args []string) error {
+	fs := a.flagSet("review list")
+	user := fs.String("user", "", "reviewer user id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *user == "" {
+		return fmt.Errorf("%w: -user is required", errUsage)
+	}
+
+	prs, err := a.client.UsersGetReview(a.ctx, *user)
+	if err != nil {
+		return err
+	}
+	resp := apitypes.UsersGetReviewResponse200{UserId: *user, PullRequests: prs}
+	return a.print(resp, func(w *table) {
+		w.row("PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS")
+		for _, pr := range prs {
+			w.row(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status)
+		}
+	})
+}
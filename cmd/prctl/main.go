@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Sugyk/avito_test_task/pkg/client"
+)
+
+const usage = `prctl is an admin tool for the PR reviewer assignment service.
+
+Usage:
+  prctl [-server URL] [-o table|json] <command> [flags]
+
+Commands:
+  team add -f team.json       create a team with members
+  team get <team_name>        show a team with its members
+  user activate <user_id>     mark a user as active
+  user deactivate <user_id>   mark a user as inactive
+  pr create -id ID -name NAME -author USER_ID
+  pr merge <pull_request_id>
+  pr reassign -id ID -old-reviewer USER_ID
+  pr add-reviewer -id ID -reviewer USER_ID
+  pr remove-reviewer -id ID -reviewer USER_ID
+  review list -user USER_ID   list pull requests assigned to a reviewer
+
+The server URL defaults to $PRCTL_SERVER or http://localhost:8080.
+`
+
+var errUsage = errors.New("invalid usage")
+
+type app struct {
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+	client *client.Client
+	output string
+	// flags is the FlagSet of the running subcommand, its usage is printed
+	// after a usage error.
+	flags *flag.FlagSet
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		// run prints usage errors itself, followed by the usage
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	server := os.Getenv("PRCTL_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+
+	a := &app{
+		ctx:    ctx,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	fs := flag.NewFlagSet("prctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	fs.StringVar(&server, "server", server, "service base URL")
+	fs.StringVar(&a.output, "o", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	a.client = client.New(server)
+
+	rest := fs.Args()
+	if len(rest) < 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	commands := map[string]func([]string) error{
+		"team add":           a.teamAdd,
+		"team get":           a.teamGet,
+		"user activate":      a.userSetIsActive(true),
+		"user deactivate":    a.userSetIsActive(false),
+		"pr create":          a.prCreate,
+		"pr merge":           a.prMerge,
+		"pr reassign":        a.prReassign,
+		"pr add-reviewer":    a.prChangeReviewer("pr add-reviewer", a.client.PullRequestAddReviewer),
+		"pr remove-reviewer": a.prChangeReviewer("pr remove-reviewer", a.client.PullRequestRemoveReviewer),
+		"review list":        a.reviewList,
+	}
+	name := rest[0] + " " + rest[1]
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		fs.Usage()
+		return errUsage
+	}
+	err := command(rest[2:])
+	if errors.Is(err, errUsage) && a.flags != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		a.flags.Usage()
+	}
+	return err
+}
+
+// flagSet creates a FlagSet for a subcommand that also accepts the output flag,
+// so it can be given after the command name.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.output, "o", a.output, "output format: table or json")
+	a.flags = fs
+	return fs
+}
+
+// positional returns the value of the flag or, if it is empty, the only
+// positional argument.
+func positional(fs *flag.FlagSet, value, name string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if fs.NArg() == 1 {
+		return fs.Arg(0), nil
+	}
+	return "", fmt.Errorf("%w: %s is required", errUsage, name)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/api"
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/pkg/apierrors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func bool_pointer(x bool) *bool {
+	return &x
+}
+
+func newTestServer(t *testing.T) (*handlers.MockService, string) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := handlers.NewMockService(ctrl)
+
+	router, err := api.NewRouter("0", handlers.NewHandler(mockService, slog.Default()))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router.Handler())
+	t.Cleanup(server.Close)
+	return mockService, server.URL
+}
+
+func runCmd(t *testing.T, url string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), append([]string{"-server", url}, args...), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestUsageErrorIsPrinted(t *testing.T) {
+	_, url := newTestServer(t)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"-server", url, "review", "list"}, &stdout, &stderr)
+	require.ErrorIs(t, err, errUsage)
+	require.Empty(t, stdout.String())
+	require.Contains(t, stderr.String(), "error: invalid usage: -user is required")
+	require.Contains(t, stderr.String(), "Usage of review list")
+
+	stderr.Reset()
+	err = run(context.Background(), []string{"-server", url, "pr", "create", "-id", "x"}, &stdout, &stderr)
+	require.ErrorIs(t, err, errUsage)
+	require.Contains(t, stderr.String(), "error: invalid usage: pull_request_name is required")
+	require.Contains(t, stderr.String(), "Usage of pr create")
+}
+
+func TestTeamAdd(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	team := &models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: bool_pointer(true)},
+		},
+	}
+	body, err := json.Marshal(team)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "team.json")
+	require.NoError(t, os.WriteFile(path, body, 0o600))
+
+	mockService.EXPECT().CreateOrUpdateTeam(gomock.Any(), team).Return(team, nil)
+
+	out, err := runCmd(t, url, "team", "add", "-f", path)
+	require.NoError(t, err)
+	require.Contains(t, out, "backend")
+	require.Contains(t, out, "Alice")
+}
+
+func TestUserDeactivate(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	user := &models.User{UserId: "u2", Username: "Bob", TeamName: "backend", IsActive: false}
+	mockService.EXPECT().UsersSetIsActive(gomock.Any(), "u2", false).Return(user, nil)
+
+	out, err := runCmd(t, url, "-o", "json", "user", "deactivate", "u2")
+	require.NoError(t, err)
+
+	var resp models.UsersSerIsActiveResponse200
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Equal(t, *user, resp.User)
+}
+
+func TestPullRequestCreate(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	req := models.PullRequestCreateRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1"}
+	pr := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+	}
+	mockService.EXPECT().PullRequestCreate(gomock.Any(), req.ToPullRequest()).Return(pr, nil)
+
+	out, err := runCmd(t, url, "pr", "create", "-id", "pr-1", "-name", "Add search", "-author", "u1")
+	require.NoError(t, err)
+	require.Contains(t, out, "u2,u3")
+}
+
+func TestPullRequestReassign_JSONAfterCommand(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	pr := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u3"}}
+	mockService.EXPECT().PullRequestReassign(gomock.Any(), "pr-1", "u2").Return(pr, "u3", nil)
+
+	out, err := runCmd(t, url, "pr", "reassign", "-id", "pr-1", "-old-reviewer", "u2", "-o", "json")
+	require.NoError(t, err)
+
+	var resp models.PullRequestReassignResponse200
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Equal(t, "u3", resp.ReplacedBy)
+}
+
+func TestPullRequestAddReviewer(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	pr := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u2", "u3"}}
+	mockService.EXPECT().PullRequestAddReviewer(gomock.Any(), "pr-1", "u3").Return(pr, nil)
+
+	out, err := runCmd(t, url, "pr", "add-reviewer", "-id", "pr-1", "-reviewer", "u3")
+	require.NoError(t, err)
+	require.Contains(t, out, "u2,u3")
+}
+
+func TestPullRequestRemoveReviewer_MissingReviewer(t *testing.T) {
+	_, url := newTestServer(t)
+
+	_, err := runCmd(t, url, "pr", "remove-reviewer", "-id", "pr-1")
+	require.ErrorIs(t, err, errUsage)
+}
+
+func TestReviewList(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	prs := []models.PullRequestShort{
+		{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", Status: models.StatusOpen},
+	}
+	mockService.EXPECT().UsersGetReview(gomock.Any(), "u2").Return(prs, nil)
+
+	out, err := runCmd(t, url, "review", "list", "-user", "u2")
+	require.NoError(t, err)
+	require.Contains(t, out, "PULL_REQUEST_ID")
+	require.Contains(t, out, "pr-1")
+}
+
+func TestServiceError(t *testing.T) {
+	mockService, url := newTestServer(t)
+
+	mockService.EXPECT().PullRequestMerge(gomock.Any(), &models.PullRequest{PullRequestId: "pr-9"}).Return(nil, models.ErrPRNotFound)
+
+	_, err := runCmd(t, url, "pr", "merge", "pr-9")
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"team"},
+		{"team", "remove"},
+		{"team", "add"},
+		{"pr", "create", "-id", "pr-1"},
+		{"review", "list"},
+	}
+	for _, args := range tests {
+		_, err := runCmd(t, "http://unused", args...)
+		require.ErrorIs(t, err, errUsage, "args: %v", args)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Sugyk/avito_test_task/pkg/apitypes"
+)
+
+type table struct {
+	tw *tabwriter.Writer
+}
+
+func (t *table) row(values ...any) {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = fmt.Sprint(v)
+	}
+	fmt.Fprintln(t.tw, strings.Join(cells, "\t"))
+}
+
+func (t *table) flush() {
+	_ = t.tw.Flush()
+}
+
+// print writes v as indented JSON or renders it as a table with fill,
+// depending on the selected output format.
+func (a *app) print(v any, fill func(*table)) error {
+	switch a.output {
+	case "json":
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "table":
+		t := &table{tw: tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)}
+		fill(t)
+		t.flush()
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown output format %q", errUsage, a.output)
+	}
+}
+
+func printTeam(w *table, team *apitypes.Team) {
+	w.row("TEAM", "USER_ID", "USERNAME", "ACTIVE")
+	for _, m := range team.Members {
+		active := m.IsActive != nil && *m.IsActive
+		w.row(team.TeamName, m.UserId, m.Username, active)
+	}
+}
+
+func printPullRequest(w *table, pr *apitypes.PullRequest) {
+	w.row("PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS")
+	w.row(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, joinOrDash(pr.AssignedReviewers))
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
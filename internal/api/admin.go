@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/health"
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// AdminRouter serves operational endpoints on a separate port, so they are
+// not exposed together with the public API.
+type AdminRouter struct {
+	server *http.Server
+}
+
+type AdminOption func(mux *http.ServeMux)
+
+// WithDataTransfer serves the import and export of all data. Requests must
+// carry token as a Bearer token.
+func WithDataTransfer(handler *handlers.Handler, token string) AdminOption {
+	return func(mux *http.ServeMux) {
+		mux.Handle("GET /admin/export", requireToken(token, http.HandlerFunc(handler.AdminExport)))
+		mux.Handle("POST /admin/import", requireToken(token, http.HandlerFunc(handler.AdminImport)))
+	}
+}
+
+// requireToken rejects requests without the Bearer token with 401. An empty
+// token rejects every request.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.Error{
+					Code:    models.UnauthorizedErrorCode,
+					Message: models.ErrUnauthorized.Error(),
+				},
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func NewAdminRouter(port string, metricsHandler http.Handler, checker *health.Checker, opts ...AdminOption) *AdminRouter {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /metrics", metricsHandler)
+	mux.HandleFunc("GET /health/live", checker.LiveHandler)
+	mux.HandleFunc("GET /health/ready", checker.ReadyHandler)
+	for _, opt := range opts {
+		opt(mux)
+	}
+
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: mux,
+	}
+	return &AdminRouter{
+		server: server,
+	}
+}
+
+func (r *AdminRouter) Handler() http.Handler {
+	return r.server.Handler
+}
+
+func (r *AdminRouter) Start() error {
+	return r.server.ListenAndServe()
+}
+
+func (r *AdminRouter) Shutdown(ctx context.Context) error {
+	if err := r.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("admin server shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/health"
+	"github.com/Sugyk/avito_test_task/internal/metrics"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdminRouter(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	admin := NewAdminRouter("0", metrics.New().Handler(), checker)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/metrics", http.StatusOK},
+		{"/health/live", http.StatusOK},
+		{"/health/ready", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			admin.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	checker.SetStarted()
+	w := httptest.NewRecorder()
+	admin.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAdminRouter_DataTransferRequiresToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := handlers.NewMockService(ctrl)
+	handler := handlers.NewHandler(mockService, slog.Default())
+	admin := NewAdminRouter("0", metrics.New().Handler(), health.NewChecker(time.Second), WithDataTransfer(handler, "secret"))
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/admin/export", nil),
+			httptest.NewRequest(http.MethodPost, "/admin/import", nil),
+		} {
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			w := httptest.NewRecorder()
+			admin.Handler().ServeHTTP(w, req)
+			require.Equal(t, http.StatusUnauthorized, w.Code, "%s %s with %q", req.Method, req.URL.Path, auth)
+			require.Contains(t, w.Body.String(), models.UnauthorizedErrorCode)
+		}
+	}
+
+	mockService.EXPECT().ExportDump(gomock.Any()).Return(&models.Dump{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	admin.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Sugyk/avito_test_task/internal/dump"
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+
+	zipContentType = "application/zip"
+	// maxImportBytes caps imports, which are far larger than API requests.
+	maxImportBytes = 64 << 20
+)
+
+// AdminExport writes all teams, users and pull requests as JSON or, with
+// format=csv, as a zip of CSV files.
+func (h *Handler) AdminExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatJSON
+	}
+	if format != formatJSON && format != formatCSV {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, fmt.Errorf("format must be %s or %s", formatJSON, formatCSV))
+		return
+	}
+
+	d, err := h.service.ExportDump(r.Context())
+	if err != nil {
+		h.log(r).Error("error exporting", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		return
+	}
+	if format == formatJSON {
+		h.sendJSON(w, r, http.StatusOK, d)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := dump.WriteZip(&buf, d); err != nil {
+		h.log(r).Error("error writing export zip", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		return
+	}
+	w.Header().Set("Content-Type", zipContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="export.zip"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// AdminImport creates or updates everything in the body, a JSON dump or a zip
+// of CSV files as written by AdminExport. With dry_run=true it only reports
+// what would change.
+func (h *Handler) AdminImport(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, fmt.Errorf("dry_run: %w", err))
+			return
+		}
+	}
+
+	// decode request
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	var d *models.Dump
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == zipContentType {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.sendDecodeError(w, r, err)
+			return
+		}
+		if d, err = dump.ReadZip(data); err != nil {
+			h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+			return
+		}
+	} else {
+		d = &models.Dump{}
+		if err := decodeJSON(r, d); err != nil {
+			h.sendDecodeError(w, r, err)
+			return
+		}
+	}
+	// business logic
+	report, err := h.service.ImportDump(r.Context(), d, dryRun)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidInput) {
+			h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+			return
+		}
+		h.log(r).Error("error importing", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, report)
+}
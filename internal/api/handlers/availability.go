@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+func (h *Handler) UsersListAvailability(w http.ResponseWriter, r *http.Request) {
+	// extract query params
+	userID := r.URL.Query().Get("user_id")
+	includePast := r.URL.Query().Get("include_past") == "true"
+	// validate params
+	if userID == "" {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing user_id"))
+		return
+	}
+	// business logic
+	periods, err := h.service.ListUnavailability(r.Context(), userID, includePast)
+	if err != nil {
+		h.sendAvailabilityError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.UnavailabilityListResponse200{
+		UserId:  userID,
+		Periods: periods,
+	})
+}
+
+func (h *Handler) UsersAddAvailability(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.UnavailabilityCreateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	period, err := h.service.CreateUnavailability(r.Context(), req.ToUnavailability())
+	if err != nil {
+		h.sendAvailabilityError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusCreated, models.UnavailabilityResponse{Period: *period})
+}
+
+func (h *Handler) UsersUpdateAvailability(w http.ResponseWriter, r *http.Request) {
+	// extract path params
+	id, ok := h.periodID(w, r)
+	if !ok {
+		return
+	}
+	// decode request
+	var req models.UnavailabilityUpdateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	period, err := h.service.UpdateUnavailability(r.Context(), req.ToUnavailability(id))
+	if err != nil {
+		h.sendAvailabilityError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.UnavailabilityResponse{Period: *period})
+}
+
+func (h *Handler) UsersDeleteAvailability(w http.ResponseWriter, r *http.Request) {
+	// extract path params
+	id, ok := h.periodID(w, r)
+	if !ok {
+		return
+	}
+	// business logic
+	period, err := h.service.DeleteUnavailability(r.Context(), id)
+	if err != nil {
+		h.sendAvailabilityError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.UnavailabilityResponse{Period: *period})
+}
+
+func (h *Handler) periodID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("id must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) sendAvailabilityError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, models.ErrUserNotFound) || errors.Is(err, models.ErrPeriodNotFound) {
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
+		return
+	}
+	h.log(r).Error("error handling unavailability", "error", err.Error())
+	h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+func (h *Handler) UsersGetExpertise(w http.ResponseWriter, r *http.Request) {
+	// extract query params
+	userID := r.URL.Query().Get("user_id")
+	// validate params
+	if userID == "" {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing user_id"))
+		return
+	}
+	// business logic
+	expertise, err := h.service.GetUserExpertise(r.Context(), userID)
+	if err != nil {
+		h.sendExpertiseError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.UserExpertiseResponse200{Expertise: *expertise})
+}
+
+func (h *Handler) UsersSetExpertise(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.UserExpertise
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	expertise, err := h.service.SetUserExpertise(r.Context(), &req)
+	if err != nil {
+		h.sendExpertiseError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.UserExpertiseResponse200{Expertise: *expertise})
+}
+
+func (h *Handler) TeamGetPathRules(w http.ResponseWriter, r *http.Request) {
+	// extract query params
+	teamName := r.URL.Query().Get("team_name")
+	// validate params
+	if teamName == "" {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing team_name"))
+		return
+	}
+	// business logic
+	rules, err := h.service.GetTeamPathRules(r.Context(), teamName)
+	if err != nil {
+		h.sendExpertiseError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.TeamPathRulesResponse200{PathRules: *rules})
+}
+
+func (h *Handler) TeamSetPathRules(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.TeamPathRules
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	rules, err := h.service.SetTeamPathRules(r.Context(), &req)
+	if err != nil {
+		h.sendExpertiseError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.TeamPathRulesResponse200{PathRules: *rules})
+}
+
+// TeamSetCodeowners replaces the CODEOWNERS file of the team. The owners of
+// the files of a new pull request are then assigned first.
+func (h *Handler) TeamSetCodeowners(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.TeamCodeownersRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	codeowners, err := h.service.SetTeamCodeowners(r.Context(), &req)
+	if err != nil {
+		h.sendExpertiseError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.TeamCodeownersResponse200{Codeowners: *codeowners})
+}
+
+func (h *Handler) sendExpertiseError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, models.ErrUserNotFound) || errors.Is(err, models.ErrTeamNotFound) {
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
+		return
+	}
+	h.log(r).Error("error handling expertise", "error", err.Error())
+	h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+}
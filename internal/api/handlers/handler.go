@@ -12,11 +12,28 @@ import (
 type Service interface {
 	CreateOrUpdateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
 	GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	SetTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error)
+	ReviewsOverdue(ctx context.Context, teamName string) ([]models.OverdueReview, error)
+	GetTeamPathRules(ctx context.Context, teamName string) (*models.TeamPathRules, error)
+	SetTeamPathRules(ctx context.Context, rules *models.TeamPathRules) (*models.TeamPathRules, error)
+	SetTeamCodeowners(ctx context.Context, req *models.TeamCodeownersRequest) (*models.TeamCodeowners, error)
 	UsersSetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
 	PullRequestCreate(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error)
 	PullRequestMerge(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error)
 	PullRequestReassign(ctx context.Context, prID string, oldUserID string) (*models.PullRequest, string, error)
+	PullRequestAddReviewer(ctx context.Context, prID string, reviewerID string) (*models.PullRequest, error)
+	PullRequestRemoveReviewer(ctx context.Context, prID string, reviewerID string) (*models.PullRequest, error)
+	PullRequestBulkCreate(ctx context.Context, prs []*models.PullRequest, atomic bool) ([]models.BulkCreateResult, error)
+	ExportDump(ctx context.Context) (*models.Dump, error)
+	ImportDump(ctx context.Context, d *models.Dump, dryRun bool) (*models.ImportReport, error)
 	UsersGetReview(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	ListUnavailability(ctx context.Context, userID string, includePast bool) ([]models.Unavailability, error)
+	CreateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error)
+	UpdateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id int64) (*models.Unavailability, error)
+	GetUserExpertise(ctx context.Context, userID string) (*models.UserExpertise, error)
+	SetUserExpertise(ctx context.Context, expertise *models.UserExpertise) (*models.UserExpertise, error)
 }
 
 type Handler struct {
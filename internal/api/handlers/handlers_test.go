@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Sugyk/avito_test_task/internal/models"
 
@@ -974,6 +978,7 @@ func TestUsersGetReview_Success(t *testing.T) {
 	h := NewHandler(mockService, slog.Default())
 
 	userID := "u2"
+	dueAt := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
 	prs := []models.PullRequestShort{
 		{
 			PullRequestId:   "pr-1001",
@@ -981,6 +986,14 @@ func TestUsersGetReview_Success(t *testing.T) {
 			AuthorId:        "u1",
 			Status:          "OPEN",
 		},
+		{
+			PullRequestId:   "pr-1002",
+			PullRequestName: "Fix search",
+			AuthorId:        "u1",
+			Status:          "OPEN",
+			DueAt:           &dueAt,
+			Overdue:         true,
+		},
 	}
 	req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id="+userID, nil)
 
@@ -995,7 +1008,10 @@ func TestUsersGetReview_Success(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, w.Code)
 
-	expectedJSON := `{"user_id":"u2","pull_requests":[{"pull_request_id":"pr-1001","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}]}`
+	expectedJSON := `{"user_id":"u2","pull_requests":[
+		{"pull_request_id":"pr-1001","pull_request_name":"Add search","author_id":"u1","status":"OPEN","overdue":false},
+		{"pull_request_id":"pr-1002","pull_request_name":"Fix search","author_id":"u1","status":"OPEN","due_at":"2026-08-01T12:00:00Z","overdue":true}
+	]}`
 	require.JSONEq(t, expectedJSON, w.Body.String())
 }
 
@@ -1013,3 +1029,837 @@ func TestUsersGetReview_MissingUserID(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, w.Code)
 	require.Contains(t, w.Body.String(), "missing user_id")
 }
+
+func TestTeamAdd_UnknownField(t *testing.T) {
+	h := NewHandler(nil, slog.Default())
+
+	body := `{"team_name": "backend", "members": [], "owner": "u1"}`
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.TeamAdd(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var outBody models.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&outBody))
+	require.Equal(t, models.InvalidInputErrorCode, outBody.Error.Code)
+	require.Contains(t, outBody.Error.Message, `unknown field "owner"`)
+}
+
+func TestPullRequestCreate_TrailingData(t *testing.T) {
+	h := NewHandler(nil, slog.Default())
+
+	body := `{"pull_request_id": "pr-1", "pull_request_name": "x", "author_id": "u1"} {}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.PullRequestCreate(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPullRequestCreate_BodyTooLarge(t *testing.T) {
+	h := NewHandler(nil, slog.Default())
+
+	body := `{"pull_request_id": "pr-1", "pull_request_name": "` + strings.Repeat("x", 128) + `", "author_id": "u1"}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(w, req.Body, 64)
+
+	h.PullRequestCreate(w, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var outBody models.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&outBody))
+	require.Equal(t, models.InvalidInputErrorCode, outBody.Error.Code)
+	require.Equal(t, models.ErrBodyTooLarge.Error(), outBody.Error.Message)
+}
+
+func TestPullRequestBulkCreate_Results(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+
+	body := `{"pull_requests": [
+		{"pull_request_id": "pr-1", "pull_request_name": "one", "author_id": "u1"},
+		{"pull_request_id": "pr-2", "pull_request_name": "", "author_id": "u1"},
+		{"pull_request_id": "pr-3", "pull_request_name": "three", "author_id": "ghost"},
+		{"pull_request_id": "pr-4", "pull_request_name": "four", "author_id": "u1"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/bulkCreate", strings.NewReader(body))
+
+	mockService.EXPECT().
+		PullRequestBulkCreate(gomock.Any(), []*models.PullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "one", AuthorId: "u1"},
+			{PullRequestId: "pr-3", PullRequestName: "three", AuthorId: "ghost"},
+			{PullRequestId: "pr-4", PullRequestName: "four", AuthorId: "u1"},
+		}, false).
+		Return([]models.BulkCreateResult{
+			{Pr: &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u2"}}},
+			{Err: models.ErrAuthorNotFound},
+			{Err: errors.New("db down")},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	NewHandler(mockService, slog.Default()).PullRequestBulkCreate(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var out models.PullRequestBulkCreateResponse200
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+	require.Equal(t, 1, out.Created)
+	require.Equal(t, 3, out.Failed)
+	require.Len(t, out.Results, 4)
+
+	require.Equal(t, models.BulkItemCreated, out.Results[0].Status)
+	require.Equal(t, []string{"u2"}, out.Results[0].Pr.AssignedReviewers)
+	require.Equal(t, models.InvalidInputErrorCode, out.Results[1].Error.Code)
+	require.Equal(t, "pr-2", out.Results[1].PullRequestId)
+	require.Equal(t, models.NotFoundErrorCode, out.Results[2].Error.Code)
+	require.Equal(t, models.InternalErrorCode, out.Results[3].Error.Code)
+	require.Equal(t, models.ErrInternalError.Error(), out.Results[3].Error.Message)
+}
+
+func TestPullRequestBulkCreate_AtomicWithInvalidItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	// the service must not be called
+	mockService := NewMockService(ctrl)
+
+	body := `{"atomic": true, "pull_requests": [
+		{"pull_request_id": "pr-1", "pull_request_name": "one", "author_id": "u1"},
+		{"pull_request_id": "pr-2", "pull_request_name": "two"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/bulkCreate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	NewHandler(mockService, slog.Default()).PullRequestBulkCreate(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var out models.PullRequestBulkCreateResponse200
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+	require.Equal(t, 0, out.Created)
+	require.Equal(t, 1, out.Failed)
+	require.Equal(t, models.BulkItemSkipped, out.Results[0].Status)
+	require.Nil(t, out.Results[0].Error)
+	require.Equal(t, models.BulkItemFailed, out.Results[1].Status)
+}
+
+func TestPullRequestBulkCreate_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "empty", body: `{"pull_requests": []}`, wantStatus: http.StatusBadRequest, wantCode: models.InvalidInputErrorCode},
+		{name: "unknown field", body: `{"pull_requests": [], "dry_run": true}`, wantStatus: http.StatusBadRequest, wantCode: models.InvalidInputErrorCode},
+		{
+			name:       "id taken during atomic create",
+			body:       `{"atomic": true, "pull_requests": [{"pull_request_id": "pr-1", "pull_request_name": "one", "author_id": "u1"}]}`,
+			serviceErr: models.ErrPRAlreadyExists,
+			wantStatus: http.StatusConflict,
+			wantCode:   models.PrExistsErrorCode,
+		},
+		{
+			name:       "service error",
+			body:       `{"pull_requests": [{"pull_request_id": "pr-1", "pull_request_name": "one", "author_id": "u1"}]}`,
+			serviceErr: errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   models.InternalErrorCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			if tt.serviceErr != nil {
+				mockService.EXPECT().
+					PullRequestBulkCreate(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/bulkCreate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			NewHandler(mockService, slog.Default()).PullRequestBulkCreate(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			var out models.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+			require.Equal(t, tt.wantCode, out.Error.Code)
+		})
+	}
+}
+
+func TestPullRequestCreate_SingleAndBulkErrorsAgree(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{models.ErrAuthorNotFound, http.StatusNotFound, models.NotFoundErrorCode},
+		{models.ErrReviewerNotFound, http.StatusNotFound, models.NotFoundErrorCode},
+		{models.ErrReviewerUnavailable, http.StatusBadRequest, models.InvalidInputErrorCode},
+		{models.ErrPRAlreadyExists, http.StatusConflict, models.PrExistsErrorCode},
+		{errors.New("db down"), http.StatusInternalServerError, models.InternalErrorCode},
+	}
+	body := `{"pull_request_id": "pr-1", "pull_request_name": "one", "author_id": "u1"}`
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := NewMockService(ctrl)
+			handler := NewHandler(mockService, slog.Default())
+
+			mockService.EXPECT().PullRequestCreate(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			handler.PullRequestCreate(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+			var single models.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&single))
+			require.Equal(t, tt.wantCode, single.Error.Code)
+
+			mockService.EXPECT().
+				PullRequestBulkCreate(gomock.Any(), gomock.Any(), false).
+				Return([]models.BulkCreateResult{{Err: tt.err}}, nil)
+			req = httptest.NewRequest(http.MethodPost, "/pullRequest/bulkCreate", strings.NewReader(`{"pull_requests": [`+body+`]}`))
+			w = httptest.NewRecorder()
+			handler.PullRequestBulkCreate(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			var bulk models.PullRequestBulkCreateResponse200
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&bulk))
+			require.Equal(t, models.BulkItemFailed, bulk.Results[0].Status)
+			require.Equal(t, single.Error, *bulk.Results[0].Error)
+		})
+	}
+}
+
+func TestAdminExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := NewMockService(ctrl)
+	d := &models.Dump{
+		Teams: []models.Team{{TeamName: "backend", Members: []models.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: bool_pointer(true)},
+		}}},
+		PullRequests: []models.PullRequest{},
+	}
+	mockService.EXPECT().ExportDump(gomock.Any()).Return(d, nil).Times(2)
+	h := NewHandler(mockService, slog.Default())
+
+	w := httptest.NewRecorder()
+	h.AdminExport(w, httptest.NewRequest(http.MethodGet, "/admin/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var out models.Dump
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+	require.Equal(t, *d, out)
+
+	w = httptest.NewRecorder()
+	h.AdminExport(w, httptest.NewRequest(http.MethodGet, "/admin/export?format=csv", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), "export.zip")
+
+	w = httptest.NewRecorder()
+	h.AdminExport(w, httptest.NewRequest(http.MethodGet, "/admin/export?format=xml", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminImport(t *testing.T) {
+	body := `{"teams": [{"team_name": "backend", "members": [{"user_id": "u1", "username": "Alice", "is_active": true}]}], "pull_requests": []}`
+
+	t.Run("dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := NewMockService(ctrl)
+		report := &models.ImportReport{
+			DryRun: true,
+			Teams:  models.EntityDiff{Created: []string{"backend"}, Updated: []string{}},
+			Users:  models.EntityDiff{Created: []string{"u1"}, Updated: []string{}},
+		}
+		mockService.EXPECT().
+			ImportDump(gomock.Any(), gomock.Any(), true).
+			DoAndReturn(func(_ any, d *models.Dump, _ bool) (*models.ImportReport, error) {
+				require.Equal(t, "backend", d.Teams[0].TeamName)
+				return report, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/import?dry_run=true", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		NewHandler(mockService, slog.Default()).AdminImport(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var out models.ImportReport
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+		require.Equal(t, *report, out)
+	})
+
+	t.Run("invalid dump", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := NewMockService(ctrl)
+		mockService.EXPECT().
+			ImportDump(gomock.Any(), gomock.Any(), false).
+			Return(nil, fmt.Errorf("%w: author not found", models.ErrInvalidInput))
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/import", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		NewHandler(mockService, slog.Default()).AdminImport(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bad zip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/import", strings.NewReader("not a zip"))
+		req.Header.Set("Content-Type", "application/zip")
+		w := httptest.NewRecorder()
+		NewHandler(nil, slog.Default()).AdminImport(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bad dry_run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/import?dry_run=maybe", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		NewHandler(nil, slog.Default()).AdminImport(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUsersAddAvailability_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	body := `{"user_id": "u1", "starts_at": "2026-08-01T00:00:00Z", "ends_at": "2026-08-15T00:00:00Z", "reason": "vacation"}`
+	req := httptest.NewRequest(http.MethodPost, "/users/availability", strings.NewReader(body))
+
+	mockService.EXPECT().
+		CreateUnavailability(req.Context(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, period *models.Unavailability) (*models.Unavailability, error) {
+			require.Equal(t, "u1", period.UserId)
+			require.Equal(t, "vacation", period.Reason)
+			period.Id = 1
+			return period, nil
+		})
+
+	w := httptest.NewRecorder()
+	h.UsersAddAvailability(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	expectedJSON := `{"period":{"id":1,"user_id":"u1","starts_at":"2026-08-01T00:00:00Z","ends_at":"2026-08-15T00:00:00Z","reason":"vacation"}}`
+	require.JSONEq(t, expectedJSON, w.Body.String())
+}
+
+func TestUsersAddAvailability_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "missing user_id",
+			body: `{"starts_at": "2026-08-01T00:00:00Z", "ends_at": "2026-08-15T00:00:00Z"}`,
+			want: "user_id is required",
+		},
+		{
+			name: "missing ends_at",
+			body: `{"user_id": "u1", "starts_at": "2026-08-01T00:00:00Z"}`,
+			want: "ends_at is required",
+		},
+		{
+			name: "ends before start",
+			body: `{"user_id": "u1", "starts_at": "2026-08-15T00:00:00Z", "ends_at": "2026-08-01T00:00:00Z"}`,
+			want: "ends_at must be after starts_at",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/users/availability", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.UsersAddAvailability(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Contains(t, w.Body.String(), tt.want)
+		})
+	}
+}
+
+func TestUsersListAvailability_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	req := httptest.NewRequest(http.MethodGet, "/users/availability?user_id=ghost", nil)
+	mockService.EXPECT().
+		ListUnavailability(req.Context(), "ghost", false).
+		Return(nil, models.ErrUserNotFound)
+
+	w := httptest.NewRecorder()
+	h.UsersListAvailability(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), models.NotFoundErrorCode)
+}
+
+func TestUsersUpdateAvailability_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	body := `{"starts_at": "2026-08-01T00:00:00Z", "ends_at": "2026-08-15T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPut, "/users/availability/42", strings.NewReader(body))
+	req.SetPathValue("id", "42")
+	mockService.EXPECT().
+		UpdateUnavailability(req.Context(), gomock.Any()).
+		Return(nil, models.ErrPeriodNotFound)
+
+	w := httptest.NewRecorder()
+	h.UsersUpdateAvailability(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), models.ErrPeriodNotFound.Error())
+}
+
+func TestUsersDeleteAvailability_InvalidID(t *testing.T) {
+	h := NewHandler(nil, slog.Default())
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/availability/abc", nil)
+	req.SetPathValue("id", "abc")
+	w := httptest.NewRecorder()
+	h.UsersDeleteAvailability(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTeamSetSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", strings.NewReader(`{"team_name": "backend", "stale_review_hours": 24}`))
+	hours := 24
+	settings := &models.TeamSettings{TeamName: "backend", StaleReviewHours: &hours}
+	mockService.EXPECT().SetTeamSettings(req.Context(), settings).Return(settings, nil)
+
+	w := httptest.NewRecorder()
+	h.TeamSetSettings(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"settings":{"team_name":"backend","stale_review_hours":24,"first_review_sla_hours":null,"merge_sla_hours":null,"min_reviewers":null,"max_reviewers":null}}`, w.Body.String())
+}
+
+func TestTeamSetSettings_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "missing team_name", body: `{"stale_review_hours": 24}`, wantStatus: http.StatusBadRequest},
+		{name: "non positive hours", body: `{"team_name": "backend", "stale_review_hours": 0}`, wantStatus: http.StatusBadRequest},
+		{name: "non positive sla", body: `{"team_name": "backend", "merge_sla_hours": -1}`, wantStatus: http.StatusBadRequest},
+		{name: "min above max", body: `{"team_name": "backend", "min_reviewers": 2, "max_reviewers": 1}`, wantStatus: http.StatusBadRequest},
+		{name: "min above default max", body: `{"team_name": "backend", "min_reviewers": 3}`, wantStatus: http.StatusBadRequest},
+		{name: "zero max", body: `{"team_name": "backend", "max_reviewers": 0}`, wantStatus: http.StatusBadRequest},
+		{name: "team not found", body: `{"team_name": "ghost"}`, serviceErr: models.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+		{name: "internal error", body: `{"team_name": "backend"}`, serviceErr: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/team/settings", strings.NewReader(tt.body))
+			if tt.serviceErr != nil {
+				mockService.EXPECT().SetTeamSettings(req.Context(), gomock.Any()).Return(nil, tt.serviceErr)
+			}
+			w := httptest.NewRecorder()
+			h.TeamSetSettings(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestReviewsOverdue(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		teamName   string
+		reviews    []models.OverdueReview
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "all teams",
+			reviews: []models.OverdueReview{{
+				PullRequestId:   "pr-1001",
+				PullRequestName: "Add search",
+				AuthorId:        "u1",
+				ReviewerId:      "u2",
+				TeamName:        "backend",
+				AssignedAt:      time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC),
+				DueAt:           time.Date(2026, 8, 2, 12, 0, 0, 0, time.UTC),
+				AgeSeconds:      172800,
+			}},
+			wantStatus: http.StatusOK,
+			wantBody: `{"reviews":[{"pull_request_id":"pr-1001","pull_request_name":"Add search","author_id":"u1","reviewer_id":"u2",
+				"team_name":"backend","assigned_at":"2026-08-01T12:00:00Z","due_at":"2026-08-02T12:00:00Z","age_seconds":172800}]}`,
+		},
+		{
+			name:       "single team",
+			query:      "?team_name=backend",
+			teamName:   "backend",
+			reviews:    []models.OverdueReview{},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"reviews":[]}`,
+		},
+		{
+			name:       "team not found",
+			query:      "?team_name=ghost",
+			teamName:   "ghost",
+			serviceErr: models.ErrTeamNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "internal error",
+			serviceErr: errors.New("db down"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodGet, "/reviews/overdue"+tt.query, nil)
+			mockService.EXPECT().ReviewsOverdue(req.Context(), tt.teamName).Return(tt.reviews, tt.serviceErr)
+
+			w := httptest.NewRecorder()
+			h.ReviewsOverdue(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				require.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPullRequestCreate_WithFilesAndLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(
+		`{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1",
+		"files": ["internal/api/server.go"], "labels": ["api"]}`))
+	mockService.EXPECT().
+		PullRequestCreate(req.Context(), &models.PullRequest{
+			PullRequestId:   "pr-1001",
+			PullRequestName: "Add search",
+			AuthorId:        "u1",
+			Files:           []string{"internal/api/server.go"},
+			Labels:          []string{"api"},
+		}).
+		Return(&models.PullRequest{PullRequestId: "pr-1001", AssignedReviewers: []string{"u2"}}, nil)
+
+	w := httptest.NewRecorder()
+	h.PullRequestCreate(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NotContains(t, w.Body.String(), "files")
+}
+
+func TestPullRequestCreate_RequiredReviewers(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: http.StatusCreated},
+		{name: "reviewer not found", serviceErr: models.ErrReviewerNotFound, wantStatus: http.StatusNotFound},
+		{name: "reviewer unavailable", serviceErr: models.ErrReviewerUnavailable, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(
+				`{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1",
+				"required_reviewers": ["u5"], "excluded_reviewers": ["u2"]}`))
+			call := mockService.EXPECT().
+				PullRequestCreate(req.Context(), &models.PullRequest{
+					PullRequestId:     "pr-1001",
+					PullRequestName:   "Add search",
+					AuthorId:          "u1",
+					RequiredReviewers: []string{"u5"},
+					ExcludedReviewers: []string{"u2"},
+				})
+			if tt.serviceErr != nil {
+				call.Return(nil, tt.serviceErr)
+			} else {
+				call.Return(&models.PullRequest{PullRequestId: "pr-1001", AssignedReviewers: []string{"u5", "u3"}}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			h.PullRequestCreate(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.NotContains(t, w.Body.String(), "required_reviewers")
+		})
+	}
+}
+
+func TestPullRequestCreate_AuthorIsRequiredReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockService(ctrl)
+	h := NewHandler(mockService, slog.Default())
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(
+		`{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1", "required_reviewers": ["u1"]}`))
+	w := httptest.NewRecorder()
+	h.PullRequestCreate(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), models.InvalidInputErrorCode)
+}
+
+func TestPullRequestAddReviewer(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "success", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, wantStatus: http.StatusOK},
+		{name: "missing reviewer_id", body: `{"pull_request_id": "pr-1"}`, wantStatus: http.StatusBadRequest, wantCode: models.InvalidInputErrorCode},
+		{name: "pr not found", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrPRNotFound, wantStatus: http.StatusNotFound, wantCode: models.NotFoundErrorCode},
+		{name: "merged", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrChangingMergedPR, wantStatus: http.StatusConflict, wantCode: models.PrMergedErrorCode},
+		{name: "already assigned", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrUserAlreadyAssigned, wantStatus: http.StatusConflict, wantCode: models.AlreadyAssignedErrorCode},
+		{name: "not a candidate", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrNotACandidate, wantStatus: http.StatusConflict, wantCode: models.NoCandidateErrorCode},
+		{name: "excluded", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrReviewerExcluded, wantStatus: http.StatusConflict, wantCode: models.NoCandidateErrorCode},
+		{name: "too many", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: models.ErrTooManyReviewers, wantStatus: http.StatusConflict, wantCode: models.ReviewerLimitErrorCode},
+		{name: "internal error", body: `{"pull_request_id": "pr-1", "reviewer_id": "u3"}`, serviceErr: errors.New("db down"), wantStatus: http.StatusInternalServerError, wantCode: models.InternalErrorCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/addReviewer", strings.NewReader(tt.body))
+			if tt.wantStatus != http.StatusBadRequest {
+				pr := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u2", "u3"}}
+				if tt.serviceErr != nil {
+					pr = nil
+				}
+				mockService.EXPECT().PullRequestAddReviewer(req.Context(), "pr-1", "u3").Return(pr, tt.serviceErr)
+			}
+			w := httptest.NewRecorder()
+			h.PullRequestAddReviewer(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode != "" {
+				require.Contains(t, w.Body.String(), tt.wantCode)
+			} else {
+				require.JSONEq(t, `{"pr":{"pull_request_id":"pr-1","pull_request_name":"","author_id":"","status":"OPEN","assigned_reviewers":["u2","u3"]}}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPullRequestRemoveReviewer(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "success", wantStatus: http.StatusOK},
+		{name: "user not found", serviceErr: models.ErrUserNotFound, wantStatus: http.StatusNotFound, wantCode: models.NotFoundErrorCode},
+		{name: "not assigned", serviceErr: models.ErrUserNotAssignedToPR, wantStatus: http.StatusConflict, wantCode: models.NotAssignedErrorCode},
+		{name: "too few", serviceErr: models.ErrTooFewReviewers, wantStatus: http.StatusConflict, wantCode: models.ReviewerLimitErrorCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/removeReviewer", strings.NewReader(`{"pull_request_id": "pr-1", "reviewer_id": "u2"}`))
+			pr := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u3"}}
+			if tt.serviceErr != nil {
+				pr = nil
+			}
+			mockService.EXPECT().PullRequestRemoveReviewer(req.Context(), "pr-1", "u2").Return(pr, tt.serviceErr)
+			w := httptest.NewRecorder()
+			h.PullRequestRemoveReviewer(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode != "" {
+				require.Contains(t, w.Body.String(), tt.wantCode)
+			} else {
+				require.Contains(t, w.Body.String(), `"assigned_reviewers":["u3"]`)
+			}
+		})
+	}
+}
+
+func TestUsersSetExpertise(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		expertise  *models.UserExpertise
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			body:       `{"user_id": "u1", "tags": ["API", "sql"]}`,
+			expertise:  &models.UserExpertise{UserId: "u1", Tags: []string{"api", "sql"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"expertise":{"user_id":"u1","tags":["api","sql"]}}`,
+		},
+		{name: "missing user_id", body: `{"tags": ["api"]}`, wantStatus: http.StatusBadRequest},
+		{name: "empty tag", body: `{"user_id": "u1", "tags": [" "]}`, wantStatus: http.StatusBadRequest},
+		{name: "user not found", body: `{"user_id": "ghost", "tags": []}`, serviceErr: models.ErrUserNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/users/expertise", strings.NewReader(tt.body))
+			if tt.expertise != nil || tt.serviceErr != nil {
+				mockService.EXPECT().SetUserExpertise(req.Context(), gomock.Any()).Return(tt.expertise, tt.serviceErr)
+			}
+			w := httptest.NewRecorder()
+			h.UsersSetExpertise(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				require.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTeamSetPathRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			body:       `{"team_name": "backend", "rules": [{"pattern": "/internal/api/", "users": ["u1"], "tags": ["api"]}]}`,
+			wantStatus: http.StatusOK,
+		},
+		{name: "missing team_name", body: `{"rules": []}`, wantStatus: http.StatusBadRequest},
+		{name: "missing pattern", body: `{"team_name": "backend", "rules": [{"users": ["u1"]}]}`, wantStatus: http.StatusBadRequest},
+		{name: "pattern with spaces", body: `{"team_name": "backend", "rules": [{"pattern": "a b"}]}`, wantStatus: http.StatusBadRequest},
+		{name: "team not found", body: `{"team_name": "ghost", "rules": []}`, serviceErr: models.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/team/pathRules", strings.NewReader(tt.body))
+			if tt.wantStatus != http.StatusBadRequest {
+				mockService.EXPECT().SetTeamPathRules(req.Context(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, rules *models.TeamPathRules) (*models.TeamPathRules, error) {
+						if tt.serviceErr != nil {
+							return nil, tt.serviceErr
+						}
+						return rules, nil
+					})
+			}
+			w := httptest.NewRecorder()
+			h.TeamSetPathRules(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestTeamSetCodeowners(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantError  string
+	}{
+		{name: "success", body: `{"team_name": "backend", "content": "* @u1\n"}`, wantStatus: http.StatusOK},
+		{name: "empty file", body: `{"team_name": "backend", "content": ""}`, wantStatus: http.StatusOK},
+		{name: "missing team_name", body: `{"content": "* @u1"}`, wantStatus: http.StatusBadRequest},
+		{
+			name:       "invalid file",
+			body:       `{"team_name": "backend", "content": "* @u1\n!*.md @u2\n"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "line 2: negated patterns are not supported",
+		},
+		{name: "team not found", body: `{"team_name": "ghost", "content": ""}`, serviceErr: models.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockService(ctrl)
+			h := NewHandler(mockService, slog.Default())
+
+			req := httptest.NewRequest(http.MethodPost, "/team/codeowners", strings.NewReader(tt.body))
+			if tt.wantStatus != http.StatusBadRequest {
+				mockService.EXPECT().SetTeamCodeowners(req.Context(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req *models.TeamCodeownersRequest) (*models.TeamCodeowners, error) {
+						if tt.serviceErr != nil {
+							return nil, tt.serviceErr
+						}
+						return &models.TeamCodeowners{TeamName: req.TeamName, Rules: []models.PathRule{}, IgnoredOwners: []string{}}, nil
+					})
+			}
+			w := httptest.NewRecorder()
+			h.TeamSetCodeowners(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				require.Contains(t, w.Body.String(), tt.wantError)
+			}
+		})
+	}
+}
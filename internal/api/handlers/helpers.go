@@ -2,21 +2,34 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 
+	"github.com/Sugyk/avito_test_task/internal/logging"
 	"github.com/Sugyk/avito_test_task/internal/models"
 )
 
-func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+// log returns the logger of the request, annotated with its request id.
+func (h *Handler) log(r *http.Request) *slog.Logger {
+	return logging.FromContext(r.Context(), h.logger)
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Error("failed to encode JSON response", "error", err.Error())
+		h.log(r).Error("failed to encode JSON response", "error", err.Error())
 	}
 }
 
-func (h *Handler) sendError(w http.ResponseWriter, status int, code string, err error) {
-	h.logger.Error("request error", code, err.Error())
+func (h *Handler) sendError(w http.ResponseWriter, r *http.Request, status int, code string, err error) {
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	h.log(r).Log(r.Context(), level, "request error", "status", status, "code", code, "error", err.Error())
 	resp := models.ErrorResponse{
 		Error: models.Error{
 			Code:    code,
@@ -24,5 +37,28 @@ func (h *Handler) sendError(w http.ResponseWriter, status int, code string, err
 		},
 	}
 
-	h.sendJSON(w, status, resp)
+	h.sendJSON(w, r, status, resp)
+}
+
+// decodeJSON decodes the request body into v. Unknown fields and anything
+// after the first JSON value are rejected.
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("request body must contain a single JSON value")
+	}
+	return nil
+}
+
+func (h *Handler) sendDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.sendError(w, r, http.StatusRequestEntityTooLarge, models.InvalidInputErrorCode, models.ErrBodyTooLarge)
+		return
+	}
+	h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -11,30 +10,25 @@ import (
 func (h *Handler) PullRequestCreate(w http.ResponseWriter, r *http.Request) {
 	// decode request
 	var req models.PullRequestCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
 		return
 	}
 	// validate request
 	if err := req.Validate(); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 		return
 	}
 	// business logic
 	pr, err := h.service.PullRequestCreate(r.Context(), req.ToPullRequest())
 	if err != nil {
-		// author/team not found
-		if errors.Is(err, models.ErrAuthorNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+		status, code := createErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			h.log(r).Error("internal error", "error", err.Error())
+			h.sendError(w, r, status, code, models.ErrInternalError)
 			return
 		}
-		// PR is already exists
-		if errors.Is(err, models.ErrPRAlreadyExists) {
-			h.sendError(w, http.StatusConflict, models.PrExistsErrorCode, err)
-			return
-		}
-		h.logger.Error("internal error", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.sendError(w, r, status, code, err)
 		return
 	}
 	// create response
@@ -42,19 +36,41 @@ func (h *Handler) PullRequestCreate(w http.ResponseWriter, r *http.Request) {
 		Pr: *pr,
 	}
 	// send response
-	h.sendJSON(w, http.StatusCreated, resp)
+	h.sendJSON(w, r, http.StatusCreated, resp)
+}
+
+// createErrorStatus maps an error of creating a pull request, alone or in a
+// bulk create, to its status and error code.
+func createErrorStatus(err error) (int, string) {
+	switch {
+	// author/team or required reviewer not found
+	case errors.Is(err, models.ErrAuthorNotFound), errors.Is(err, models.ErrReviewerNotFound):
+		return http.StatusNotFound, models.NotFoundErrorCode
+	// required reviewer can not review or leaves a code owner out, or the id
+	// is not one of the code host
+	case errors.Is(err, models.ErrReviewerUnavailable), errors.Is(err, models.ErrNoSlotForCodeOwner),
+		errors.Is(err, models.ErrUnsupportedPRID), errors.Is(err, models.ErrAuthorWithoutTeam):
+		return http.StatusBadRequest, models.InvalidInputErrorCode
+	// PR is already exists
+	case errors.Is(err, models.ErrPRAlreadyExists):
+		return http.StatusConflict, models.PrExistsErrorCode
+	// more required reviewers than the team allows
+	case errors.Is(err, models.ErrTooManyRequired):
+		return http.StatusConflict, models.ReviewerLimitErrorCode
+	}
+	return http.StatusInternalServerError, models.InternalErrorCode
 }
 
 func (h *Handler) PullRequestMerge(w http.ResponseWriter, r *http.Request) {
 	// decode request
 	var req models.PullRequestMergeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
 		return
 	}
 	// validate request
 	if err := req.Validate(); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 		return
 	}
 	// business logic
@@ -62,12 +78,12 @@ func (h *Handler) PullRequestMerge(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// pr not found
 		if errors.Is(err, models.ErrPRNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+			h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
 			return
 		}
 		// PR is already exists
-		h.logger.Error("internal error", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.log(r).Error("internal error", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 		return
 	}
 	// create response
@@ -75,19 +91,19 @@ func (h *Handler) PullRequestMerge(w http.ResponseWriter, r *http.Request) {
 		Pr: *pr,
 	}
 	// send response
-	h.sendJSON(w, http.StatusOK, resp)
+	h.sendJSON(w, r, http.StatusOK, resp)
 }
 
 func (h *Handler) PullRequestReassign(w http.ResponseWriter, r *http.Request) {
 	// decode request
 	var req models.PullRequestReassignRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
 		return
 	}
 	// validate request
 	if err := req.Validate(); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 		return
 	}
 	// business logic
@@ -96,32 +112,32 @@ func (h *Handler) PullRequestReassign(w http.ResponseWriter, r *http.Request) {
 		// 404
 		// pr not found
 		if errors.Is(err, models.ErrPRNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+			h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
 			return
 		}
 		// user not found
 		if errors.Is(err, models.ErrUserNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+			h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
 			return
 		}
 		// 409
 		// reassigning merged pr
 		if errors.Is(err, models.ErrReassigningMergedPR) {
-			h.sendError(w, http.StatusConflict, models.PrMergedErrorCode, err)
+			h.sendError(w, r, http.StatusConflict, models.PrMergedErrorCode, err)
 			return
 		}
 		// not assigned user
 		if errors.Is(err, models.ErrUserNotAssignedToPR) {
-			h.sendError(w, http.StatusConflict, models.NotAssignedErrorCode, err)
+			h.sendError(w, r, http.StatusConflict, models.NotAssignedErrorCode, err)
 			return
 		}
 		// no candidates
 		if errors.Is(err, models.ErrNoActiveCandidates) {
-			h.sendError(w, http.StatusConflict, models.NoCandidateErrorCode, err)
+			h.sendError(w, r, http.StatusConflict, models.NoCandidateErrorCode, err)
 			return
 		}
-		h.logger.Error("error reassigning reviewer", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.log(r).Error("error reassigning reviewer", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 		return
 	}
 	// create response
@@ -130,5 +146,151 @@ func (h *Handler) PullRequestReassign(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy: replacedBy,
 	}
 	// send response
-	h.sendJSON(w, http.StatusOK, resp)
+	h.sendJSON(w, r, http.StatusOK, resp)
+}
+
+// PullRequestAddReviewer assigns one more reviewer to an open pull request.
+func (h *Handler) PullRequestAddReviewer(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.PullRequestReviewerRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	pr, err := h.service.PullRequestAddReviewer(r.Context(), req.PullRequestId, req.ReviewerId)
+	if err != nil {
+		h.sendReviewerError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.PullRequestReviewerResponse200{Pr: *pr})
+}
+
+// PullRequestRemoveReviewer unassigns a reviewer from an open pull request.
+func (h *Handler) PullRequestRemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.PullRequestReviewerRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	pr, err := h.service.PullRequestRemoveReviewer(r.Context(), req.PullRequestId, req.ReviewerId)
+	if err != nil {
+		h.sendReviewerError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.PullRequestReviewerResponse200{Pr: *pr})
+}
+
+// sendReviewerError maps the errors of adding and removing a reviewer.
+func (h *Handler) sendReviewerError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrPRNotFound), errors.Is(err, models.ErrUserNotFound):
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
+	case errors.Is(err, models.ErrChangingMergedPR):
+		h.sendError(w, r, http.StatusConflict, models.PrMergedErrorCode, err)
+	case errors.Is(err, models.ErrUserAlreadyAssigned):
+		h.sendError(w, r, http.StatusConflict, models.AlreadyAssignedErrorCode, err)
+	case errors.Is(err, models.ErrUserNotAssignedToPR):
+		h.sendError(w, r, http.StatusConflict, models.NotAssignedErrorCode, err)
+	case errors.Is(err, models.ErrNotACandidate), errors.Is(err, models.ErrReviewerExcluded):
+		h.sendError(w, r, http.StatusConflict, models.NoCandidateErrorCode, err)
+	case errors.Is(err, models.ErrTooManyReviewers), errors.Is(err, models.ErrTooFewReviewers):
+		h.sendError(w, r, http.StatusConflict, models.ReviewerLimitErrorCode, err)
+	default:
+		h.log(r).Error("error changing reviewers", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+	}
+}
+
+func (h *Handler) PullRequestBulkCreate(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.PullRequestBulkCreateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request, invalid items fail on their own
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	items := make([]models.PullRequestBulkCreateItem, len(req.PullRequests))
+	prs := make([]*models.PullRequest, 0, len(req.PullRequests))
+	indexes := make([]int, 0, len(req.PullRequests))
+	for i, pr := range req.PullRequests {
+		items[i] = models.PullRequestBulkCreateItem{
+			Index:         i,
+			PullRequestId: pr.PullRequestId,
+			Status:        models.BulkItemSkipped,
+		}
+		if err := pr.Validate(); err != nil {
+			items[i].Status = models.BulkItemFailed
+			items[i].Error = &models.Error{Code: models.InvalidInputErrorCode, Message: err.Error()}
+			continue
+		}
+		prs = append(prs, pr.ToPullRequest())
+		indexes = append(indexes, i)
+	}
+	// business logic
+	if len(prs) > 0 && (len(prs) == len(items) || !req.Atomic) {
+		results, err := h.service.PullRequestBulkCreate(r.Context(), prs, req.Atomic)
+		if err != nil {
+			// e.g. an id was taken while an atomic request was running
+			status, code := createErrorStatus(err)
+			if status == http.StatusInternalServerError {
+				h.log(r).Error("error creating pull requests", "error", err.Error())
+				h.sendError(w, r, status, code, models.ErrInternalError)
+				return
+			}
+			h.sendError(w, r, status, code, err)
+			return
+		}
+		for j, result := range results {
+			item := &items[indexes[j]]
+			switch {
+			case result.Err != nil:
+				item.Status = models.BulkItemFailed
+				item.Error = h.bulkItemError(r, result.Err)
+			case result.Pr != nil:
+				item.Status = models.BulkItemCreated
+				item.Pr = result.Pr
+			}
+		}
+	}
+	// create response
+	resp := models.PullRequestBulkCreateResponse200{Results: items}
+	for _, item := range items {
+		switch item.Status {
+		case models.BulkItemCreated:
+			resp.Created++
+		case models.BulkItemFailed:
+			resp.Failed++
+		}
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, resp)
+}
+
+// bulkItemError maps the error of a single bulk item to its error code.
+func (h *Handler) bulkItemError(r *http.Request, err error) *models.Error {
+	status, code := createErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.log(r).Error("error creating pull request", "error", err.Error())
+		return &models.Error{Code: code, Message: models.ErrInternalError.Error()}
+	}
+	return &models.Error{Code: code, Message: err.Error()}
 }
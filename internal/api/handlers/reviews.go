@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// ReviewsOverdue lists the reviews past the SLA of their reviewer's team,
+// of a single team with the team_name query parameter.
+func (h *Handler) ReviewsOverdue(w http.ResponseWriter, r *http.Request) {
+	// extract query params
+	teamName := r.URL.Query().Get("team_name")
+	// business logic
+	reviews, err := h.service.ReviewsOverdue(r.Context(), teamName)
+	if errors.Is(err, models.ErrTeamNotFound) {
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
+		return
+	}
+	if err != nil {
+		h.log(r).Error("error listing overdue reviews", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.OverdueReviewsResponse200{Reviews: reviews})
+}
@@ -56,6 +56,81 @@ func (mr *MockServiceMockRecorder) CreateOrUpdateTeam(ctx, team any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateTeam", reflect.TypeOf((*MockService)(nil).CreateOrUpdateTeam), ctx, team)
 }
 
+// CreateUnavailability mocks base method.
+func (m *MockService) CreateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUnavailability", ctx, period)
+	ret0, _ := ret[0].(*models.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUnavailability indicates an expected call of CreateUnavailability.
+func (mr *MockServiceMockRecorder) CreateUnavailability(ctx, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnavailability", reflect.TypeOf((*MockService)(nil).CreateUnavailability), ctx, period)
+}
+
+// DeleteUnavailability mocks base method.
+func (m *MockService) DeleteUnavailability(ctx context.Context, id int64) (*models.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnavailability", ctx, id)
+	ret0, _ := ret[0].(*models.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUnavailability indicates an expected call of DeleteUnavailability.
+func (mr *MockServiceMockRecorder) DeleteUnavailability(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnavailability", reflect.TypeOf((*MockService)(nil).DeleteUnavailability), ctx, id)
+}
+
+// ExportDump mocks base method.
+func (m *MockService) ExportDump(ctx context.Context) (*models.Dump, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportDump", ctx)
+	ret0, _ := ret[0].(*models.Dump)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportDump indicates an expected call of ExportDump.
+func (mr *MockServiceMockRecorder) ExportDump(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportDump", reflect.TypeOf((*MockService)(nil).ExportDump), ctx)
+}
+
+// GetTeamPathRules mocks base method.
+func (m *MockService) GetTeamPathRules(ctx context.Context, teamName string) (*models.TeamPathRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamPathRules", ctx, teamName)
+	ret0, _ := ret[0].(*models.TeamPathRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamPathRules indicates an expected call of GetTeamPathRules.
+func (mr *MockServiceMockRecorder) GetTeamPathRules(ctx, teamName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamPathRules", reflect.TypeOf((*MockService)(nil).GetTeamPathRules), ctx, teamName)
+}
+
+// GetTeamSettings mocks base method.
+func (m *MockService) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamSettings", ctx, teamName)
+	ret0, _ := ret[0].(*models.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamSettings indicates an expected call of GetTeamSettings.
+func (mr *MockServiceMockRecorder) GetTeamSettings(ctx, teamName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamSettings", reflect.TypeOf((*MockService)(nil).GetTeamSettings), ctx, teamName)
+}
+
 // GetTeamWithMembers mocks base method.
 func (m *MockService) GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +146,81 @@ func (mr *MockServiceMockRecorder) GetTeamWithMembers(ctx, teamName any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamWithMembers", reflect.TypeOf((*MockService)(nil).GetTeamWithMembers), ctx, teamName)
 }
 
+// GetUserExpertise mocks base method.
+func (m *MockService) GetUserExpertise(ctx context.Context, userID string) (*models.UserExpertise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserExpertise", ctx, userID)
+	ret0, _ := ret[0].(*models.UserExpertise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserExpertise indicates an expected call of GetUserExpertise.
+func (mr *MockServiceMockRecorder) GetUserExpertise(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserExpertise", reflect.TypeOf((*MockService)(nil).GetUserExpertise), ctx, userID)
+}
+
+// ImportDump mocks base method.
+func (m *MockService) ImportDump(ctx context.Context, d *models.Dump, dryRun bool) (*models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportDump", ctx, d, dryRun)
+	ret0, _ := ret[0].(*models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportDump indicates an expected call of ImportDump.
+func (mr *MockServiceMockRecorder) ImportDump(ctx, d, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDump", reflect.TypeOf((*MockService)(nil).ImportDump), ctx, d, dryRun)
+}
+
+// ListUnavailability mocks base method.
+func (m *MockService) ListUnavailability(ctx context.Context, userID string, includePast bool) ([]models.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnavailability", ctx, userID, includePast)
+	ret0, _ := ret[0].([]models.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnavailability indicates an expected call of ListUnavailability.
+func (mr *MockServiceMockRecorder) ListUnavailability(ctx, userID, includePast any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnavailability", reflect.TypeOf((*MockService)(nil).ListUnavailability), ctx, userID, includePast)
+}
+
+// PullRequestAddReviewer mocks base method.
+func (m *MockService) PullRequestAddReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestAddReviewer", ctx, prID, reviewerID)
+	ret0, _ := ret[0].(*models.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullRequestAddReviewer indicates an expected call of PullRequestAddReviewer.
+func (mr *MockServiceMockRecorder) PullRequestAddReviewer(ctx, prID, reviewerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestAddReviewer", reflect.TypeOf((*MockService)(nil).PullRequestAddReviewer), ctx, prID, reviewerID)
+}
+
+// PullRequestBulkCreate mocks base method.
+func (m *MockService) PullRequestBulkCreate(ctx context.Context, prs []*models.PullRequest, atomic bool) ([]models.BulkCreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestBulkCreate", ctx, prs, atomic)
+	ret0, _ := ret[0].([]models.BulkCreateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullRequestBulkCreate indicates an expected call of PullRequestBulkCreate.
+func (mr *MockServiceMockRecorder) PullRequestBulkCreate(ctx, prs, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestBulkCreate", reflect.TypeOf((*MockService)(nil).PullRequestBulkCreate), ctx, prs, atomic)
+}
+
 // PullRequestCreate mocks base method.
 func (m *MockService) PullRequestCreate(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +267,111 @@ func (mr *MockServiceMockRecorder) PullRequestReassign(ctx, prID, oldUserID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestReassign", reflect.TypeOf((*MockService)(nil).PullRequestReassign), ctx, prID, oldUserID)
 }
 
+// PullRequestRemoveReviewer mocks base method.
+func (m *MockService) PullRequestRemoveReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestRemoveReviewer", ctx, prID, reviewerID)
+	ret0, _ := ret[0].(*models.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullRequestRemoveReviewer indicates an expected call of PullRequestRemoveReviewer.
+func (mr *MockServiceMockRecorder) PullRequestRemoveReviewer(ctx, prID, reviewerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestRemoveReviewer", reflect.TypeOf((*MockService)(nil).PullRequestRemoveReviewer), ctx, prID, reviewerID)
+}
+
+// ReviewsOverdue mocks base method.
+func (m *MockService) ReviewsOverdue(ctx context.Context, teamName string) ([]models.OverdueReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewsOverdue", ctx, teamName)
+	ret0, _ := ret[0].([]models.OverdueReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewsOverdue indicates an expected call of ReviewsOverdue.
+func (mr *MockServiceMockRecorder) ReviewsOverdue(ctx, teamName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewsOverdue", reflect.TypeOf((*MockService)(nil).ReviewsOverdue), ctx, teamName)
+}
+
+// SetTeamCodeowners mocks base method.
+func (m *MockService) SetTeamCodeowners(ctx context.Context, req *models.TeamCodeownersRequest) (*models.TeamCodeowners, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTeamCodeowners", ctx, req)
+	ret0, _ := ret[0].(*models.TeamCodeowners)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTeamCodeowners indicates an expected call of SetTeamCodeowners.
+func (mr *MockServiceMockRecorder) SetTeamCodeowners(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTeamCodeowners", reflect.TypeOf((*MockService)(nil).SetTeamCodeowners), ctx, req)
+}
+
+// SetTeamPathRules mocks base method.
+func (m *MockService) SetTeamPathRules(ctx context.Context, rules *models.TeamPathRules) (*models.TeamPathRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTeamPathRules", ctx, rules)
+	ret0, _ := ret[0].(*models.TeamPathRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTeamPathRules indicates an expected call of SetTeamPathRules.
+func (mr *MockServiceMockRecorder) SetTeamPathRules(ctx, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTeamPathRules", reflect.TypeOf((*MockService)(nil).SetTeamPathRules), ctx, rules)
+}
+
+// SetTeamSettings mocks base method.
+func (m *MockService) SetTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTeamSettings", ctx, settings)
+	ret0, _ := ret[0].(*models.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTeamSettings indicates an expected call of SetTeamSettings.
+func (mr *MockServiceMockRecorder) SetTeamSettings(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTeamSettings", reflect.TypeOf((*MockService)(nil).SetTeamSettings), ctx, settings)
+}
+
+// SetUserExpertise mocks base method.
+func (m *MockService) SetUserExpertise(ctx context.Context, expertise *models.UserExpertise) (*models.UserExpertise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserExpertise", ctx, expertise)
+	ret0, _ := ret[0].(*models.UserExpertise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserExpertise indicates an expected call of SetUserExpertise.
+func (mr *MockServiceMockRecorder) SetUserExpertise(ctx, expertise any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserExpertise", reflect.TypeOf((*MockService)(nil).SetUserExpertise), ctx, expertise)
+}
+
+// UpdateUnavailability mocks base method.
+func (m *MockService) UpdateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUnavailability", ctx, period)
+	ret0, _ := ret[0].(*models.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUnavailability indicates an expected call of UpdateUnavailability.
+func (mr *MockServiceMockRecorder) UpdateUnavailability(ctx, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUnavailability", reflect.TypeOf((*MockService)(nil).UpdateUnavailability), ctx, period)
+}
+
 // UsersGetReview mocks base method.
 func (m *MockService) UsersGetReview(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	m.ctrl.T.Helper()
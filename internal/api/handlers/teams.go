@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -11,13 +10,13 @@ import (
 func (h *Handler) TeamAdd(w http.ResponseWriter, r *http.Request) {
 	// decode request
 	var req models.Team
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
 		return
 	}
 	// validate request
 	if err := req.Validate(); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 		return
 	}
 	// business logic
@@ -25,11 +24,11 @@ func (h *Handler) TeamAdd(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// team_name already exists
 		if errors.Is(err, models.ErrTeamExists) {
-			h.sendError(w, http.StatusBadRequest, models.TeamExistsErrorCode, err)
+			h.sendError(w, r, http.StatusBadRequest, models.TeamExistsErrorCode, err)
 			return
 		}
-		h.logger.Error("internal error", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.log(r).Error("internal error", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 		return
 	}
 	// create response
@@ -37,7 +36,7 @@ func (h *Handler) TeamAdd(w http.ResponseWriter, r *http.Request) {
 		Team: *team,
 	}
 	// send response
-	h.sendJSON(w, http.StatusCreated, resp)
+	h.sendJSON(w, r, http.StatusCreated, resp)
 }
 
 func (h *Handler) TeamGet(w http.ResponseWriter, r *http.Request) {
@@ -45,16 +44,65 @@ func (h *Handler) TeamGet(w http.ResponseWriter, r *http.Request) {
 	teamName := r.URL.Query().Get("team_name")
 	// validate params
 	if teamName == "" {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing team_name"))
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing team_name"))
 		return
 	}
 	// business logic
 	team, err := h.service.GetTeamWithMembers(r.Context(), teamName)
 	if err != nil {
 		// team not found
-		h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
 		return
 	}
 	// send response
-	h.sendJSON(w, http.StatusOK, *team)
+	h.sendJSON(w, r, http.StatusOK, *team)
+}
+
+func (h *Handler) TeamGetSettings(w http.ResponseWriter, r *http.Request) {
+	// extract query params
+	teamName := r.URL.Query().Get("team_name")
+	// validate params
+	if teamName == "" {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing team_name"))
+		return
+	}
+	// business logic
+	settings, err := h.service.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		h.sendSettingsError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.TeamSettingsResponse200{Settings: *settings})
+}
+
+func (h *Handler) TeamSetSettings(w http.ResponseWriter, r *http.Request) {
+	// decode request
+	var req models.TeamSettings
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
+		return
+	}
+	// validate request
+	if err := req.Validate(); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		return
+	}
+	// business logic
+	settings, err := h.service.SetTeamSettings(r.Context(), &req)
+	if err != nil {
+		h.sendSettingsError(w, r, err)
+		return
+	}
+	// send response
+	h.sendJSON(w, r, http.StatusOK, models.TeamSettingsResponse200{Settings: *settings})
+}
+
+func (h *Handler) sendSettingsError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, models.ErrTeamNotFound) {
+		h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
+		return
+	}
+	h.log(r).Error("error handling team settings", "error", err.Error())
+	h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -11,13 +10,13 @@ import (
 func (h *Handler) UsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	// decode request
 	var req models.UsersSetIsActiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+	if err := decodeJSON(r, &req); err != nil {
+		h.sendDecodeError(w, r, err)
 		return
 	}
 	// validate request
 	if err := req.Validate(); err != nil {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, err)
 		return
 	}
 	// business logic
@@ -25,11 +24,11 @@ func (h *Handler) UsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// user not found
 		if errors.Is(err, models.ErrUserNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, err)
+			h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, err)
 			return
 		}
-		h.logger.Error("internal error", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.log(r).Error("internal error", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 		return
 	}
 	// create response
@@ -37,7 +36,7 @@ func (h *Handler) UsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 		User: *user,
 	}
 	// send response
-	h.sendJSON(w, http.StatusOK, resp)
+	h.sendJSON(w, r, http.StatusOK, resp)
 }
 
 func (h *Handler) UsersGetReview(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +44,7 @@ func (h *Handler) UsersGetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	// validate params
 	if userID == "" {
-		h.sendError(w, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing user_id"))
+		h.sendError(w, r, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("missing user_id"))
 		return
 	}
 	// business logic
@@ -53,11 +52,11 @@ func (h *Handler) UsersGetReview(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// user not found
 		if errors.Is(err, models.ErrUserNotFound) {
-			h.sendError(w, http.StatusNotFound, models.NotFoundErrorCode, models.ErrUserNotFound)
+			h.sendError(w, r, http.StatusNotFound, models.NotFoundErrorCode, models.ErrUserNotFound)
 			return
 		}
-		h.logger.Error("error getting user's reviews", "error", err.Error())
-		h.sendError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+		h.log(r).Error("error getting user's reviews", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
 	}
 	// create response
 	resp := models.UsersGetReviewResponse200{
@@ -65,5 +64,5 @@ func (h *Handler) UsersGetReview(w http.ResponseWriter, r *http.Request) {
 		PullRequests: prs,
 	}
 	// send response
-	h.sendJSON(w, http.StatusOK, resp)
+	h.sendJSON(w, r, http.StatusOK, resp)
 }
@@ -3,35 +3,249 @@ package api
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/api/openapi"
+	"github.com/Sugyk/avito_test_task/internal/idempotency"
+	"github.com/Sugyk/avito_test_task/internal/logging"
+	"github.com/Sugyk/avito_test_task/internal/metrics"
+	"github.com/Sugyk/avito_test_task/internal/ratelimit"
+	"github.com/Sugyk/avito_test_task/internal/scim"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
 )
 
 type Router struct {
 	server   *http.Server
 	handlers *handlers.Handler
+	patterns []string
 }
 
-func NewRouter(port string, handler *handlers.Handler) *Router {
+type RouterOption func(*routerOptions)
+
+type routerOptions struct {
+	graphql http.Handler
+	scim    http.Handler
+	metrics *metrics.Metrics
+	tracing bool
+	logger  *slog.Logger
+	noDocs  bool
+
+	limiter      *ratelimit.Limiter
+	maxBodyBytes int64
+
+	idempotencyStore  idempotency.Store
+	idempotencyClient func(*http.Request) string
+	idempotencyLogger *slog.Logger
+
+	timeouts Timeouts
+}
+
+// Timeouts of the HTTP server, zero means no timeout.
+type Timeouts struct {
+	Read       time.Duration
+	ReadHeader time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+}
+
+// WithGraphQL serves the GraphQL endpoint at POST /graphql.
+func WithGraphQL(handler http.Handler) RouterOption {
+	return func(o *routerOptions) {
+		o.graphql = handler
+	}
+}
+
+// WithSCIM serves the SCIM endpoints of handler under /scim/v2/.
+func WithSCIM(handler http.Handler) RouterOption {
+	return func(o *routerOptions) {
+		o.scim = handler
+	}
+}
+
+// WithMetrics records request durations per route.
+func WithMetrics(m *metrics.Metrics) RouterOption {
+	return func(o *routerOptions) {
+		o.metrics = m
+	}
+}
+
+// WithTracing starts a span per request, continuing incoming W3C traces.
+func WithTracing() RouterOption {
+	return func(o *routerOptions) {
+		o.tracing = true
+	}
+}
+
+// WithRequestLogging assigns request ids and logs every request with logger.
+func WithRequestLogging(logger *slog.Logger) RouterOption {
+	return func(o *routerOptions) {
+		o.logger = logger
+	}
+}
+
+// WithoutDocs disables GET /openapi.json and GET /docs.
+func WithoutDocs() RouterOption {
+	return func(o *routerOptions) {
+		o.noDocs = true
+	}
+}
+
+// WithTimeouts sets the timeouts of the HTTP server.
+func WithTimeouts(t Timeouts) RouterOption {
+	return func(o *routerOptions) {
+		o.timeouts = t
+	}
+}
+
+// WithRateLimit throttles clients with limiter.
+func WithRateLimit(limiter *ratelimit.Limiter) RouterOption {
+	return func(o *routerOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithMaxBodyBytes rejects request bodies larger than n bytes.
+func WithMaxBodyBytes(n int64) RouterOption {
+	return func(o *routerOptions) {
+		o.maxBodyBytes = n
+	}
+}
+
+// WithIdempotency honors the Idempotency-Key header on the routes that are
+// not safe to retry, storing responses in store per client as identified by
+// clientKey.
+func WithIdempotency(store idempotency.Store, clientKey func(*http.Request) string, logger *slog.Logger) RouterOption {
+	return func(o *routerOptions) {
+		o.idempotencyStore = store
+		o.idempotencyClient = clientKey
+		o.idempotencyLogger = logger
+	}
+}
+
+// idempotentRoutes are the mutating routes whose replay changes the result.
+var idempotentRoutes = []string{
+	"POST /pullRequest/create",
+	"POST /pullRequest/reassign",
+	"POST /pullRequest/addReviewer",
+	"POST /pullRequest/removeReviewer",
+	"POST /pullRequest/bulkCreate",
+}
+
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+}
+
+func apiRoutes(handler *handlers.Handler) []route {
+	return []route{
+		{"POST /team/add", handler.TeamAdd},
+		{"GET /team/get", handler.TeamGet},
+		{"GET /team/settings", handler.TeamGetSettings},
+		{"POST /team/settings", handler.TeamSetSettings},
+		{"GET /team/pathRules", handler.TeamGetPathRules},
+		{"POST /team/pathRules", handler.TeamSetPathRules},
+		{"POST /team/codeowners", handler.TeamSetCodeowners},
+		{"POST /users/setIsActive", handler.UsersSetIsActive},
+		{"POST /pullRequest/create", handler.PullRequestCreate},
+		{"POST /pullRequest/merge", handler.PullRequestMerge},
+		{"POST /pullRequest/reassign", handler.PullRequestReassign},
+		{"POST /pullRequest/addReviewer", handler.PullRequestAddReviewer},
+		{"POST /pullRequest/removeReviewer", handler.PullRequestRemoveReviewer},
+		{"POST /pullRequest/bulkCreate", handler.PullRequestBulkCreate},
+		{"GET /users/getReview", handler.UsersGetReview},
+		{"GET /users/availability", handler.UsersListAvailability},
+		{"POST /users/availability", handler.UsersAddAvailability},
+		{"PUT /users/availability/{id}", handler.UsersUpdateAvailability},
+		{"DELETE /users/availability/{id}", handler.UsersDeleteAvailability},
+		{"GET /users/expertise", handler.UsersGetExpertise},
+		{"POST /users/expertise", handler.UsersSetExpertise},
+		{"GET /reviews/overdue", handler.ReviewsOverdue},
+	}
+}
+
+func NewRouter(port string, handler *handlers.Handler, opts ...RouterOption) (*Router, error) {
+	var options routerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	doc, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	specHandler, err := openapi.SpecHandler(doc)
+	if err != nil {
+		return nil, err
+	}
+	validate, err := openapi.ValidationMiddleware(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := apiRoutes(handler)
+	if !options.noDocs {
+		routes = append(routes,
+			route{"GET /openapi.json", specHandler},
+			route{"GET /docs", openapi.SwaggerUIHandler},
+		)
+	}
+	if options.graphql != nil {
+		routes = append(routes, route{"POST /graphql", options.graphql.ServeHTTP})
+	}
+
 	mux := http.NewServeMux()
+	patterns := make([]string, 0, len(routes))
+	for _, rt := range routes {
+		mux.HandleFunc(rt.pattern, rt.handler)
+		patterns = append(patterns, rt.pattern)
+	}
+	// SCIM is specified by RFC 7644 rather than by the OpenAPI spec, so it
+	// is left out of patterns; the validation passes it through as unknown.
+	if options.scim != nil {
+		mux.Handle(scim.BasePath+"/", options.scim)
+	}
 
-	mux.HandleFunc("POST /team/add", handler.TeamAdd)
-	mux.HandleFunc("GET /team/get", handler.TeamGet)
-	mux.HandleFunc("POST /users/setIsActive", handler.UsersSetIsActive)
-	mux.HandleFunc("POST /pullRequest/create", handler.PullRequestCreate)
-	mux.HandleFunc("POST /pullRequest/merge", handler.PullRequestMerge)
-	mux.HandleFunc("POST /pullRequest/reassign", handler.PullRequestReassign)
-	mux.HandleFunc("GET /users/getReview", handler.UsersGetReview)
+	root := validate(mux)
+	if options.idempotencyStore != nil {
+		root = idempotency.Middleware(options.idempotencyStore, mux, idempotentRoutes, options.idempotencyClient, options.idempotencyLogger)(root)
+	}
+	if options.maxBodyBytes > 0 {
+		root = ratelimit.MaxBytes(options.maxBodyBytes)(root)
+	}
+	if options.limiter != nil {
+		root = options.limiter.Middleware(mux)(root)
+	}
+	if options.metrics != nil {
+		root = options.metrics.Middleware(mux)(root)
+	}
+	if options.logger != nil {
+		root = logging.Middleware(options.logger)(root)
+	}
+	if options.tracing {
+		root = tracing.Middleware(mux)(root)
+	}
 
 	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: mux,
+		Addr:              ":" + port,
+		Handler:           root,
+		ReadTimeout:       options.timeouts.Read,
+		ReadHeaderTimeout: options.timeouts.ReadHeader,
+		WriteTimeout:      options.timeouts.Write,
+		IdleTimeout:       options.timeouts.Idle,
 	}
 	return &Router{
 		server:   server,
 		handlers: handler,
-	}
+		patterns: patterns,
+	}, nil
+}
+
+// Handler returns the root HTTP handler, including all middlewares.
+func (r *Router) Handler() http.Handler {
+	return r.server.Handler
 }
 
 func (r *Router) Start() error {
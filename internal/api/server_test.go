@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/api/openapi"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/ratelimit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoutesHaveSpecEntries(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	router, err := NewRouter("0", handlers.NewHandler(nil, slog.Default()), WithGraphQL(http.NotFoundHandler()))
+	require.NoError(t, err)
+	require.NotEmpty(t, router.patterns)
+
+	for _, pattern := range router.patterns {
+		method, path, found := strings.Cut(pattern, " ")
+		require.True(t, found, "route %q must declare a method", pattern)
+
+		item := doc.Paths.Find(path)
+		require.NotNil(t, item, "route %q has no path in the OpenAPI spec", pattern)
+		require.NotNil(t, item.GetOperation(method), "route %q has no operation in the OpenAPI spec", pattern)
+	}
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router, err := NewRouter("0", handlers.NewHandler(handlers.NewMockService(ctrl), slog.Default()))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{
+			name:   "missing required field",
+			method: http.MethodPost,
+			target: "/pullRequest/create",
+			body:   `{"pull_request_id": "pr-1", "author_id": "u1"}`,
+		},
+		{
+			name:   "wrong field type",
+			method: http.MethodPost,
+			target: "/users/setIsActive",
+			body:   `{"user_id": "u1", "is_active": "yes"}`,
+		},
+		{
+			name:   "missing query param",
+			method: http.MethodGet,
+			target: "/users/getReview",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var out models.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+			require.Equal(t, models.InvalidInputErrorCode, out.Error.Code)
+		})
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	router, err := NewRouter("0", handlers.NewHandler(nil, slog.Default()))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var spec map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&spec))
+	require.Contains(t, spec, "paths")
+}
+
+func TestValidationMiddleware_PassesValidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := handlers.NewMockService(ctrl)
+	router, err := NewRouter("0", handlers.NewHandler(mockService, slog.Default()))
+	require.NoError(t, err)
+
+	mockService.
+		EXPECT().
+		PullRequestMerge(gomock.Any(), &models.PullRequest{PullRequestId: "pr-1"}).
+		Return(&models.PullRequest{PullRequestId: "pr-1", Status: models.StatusMerged}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBufferString(`{"pull_request_id": "pr-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.Handler().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouterLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limiter := ratelimit.New(ratelimit.Config{
+		Routes: map[string]ratelimit.Rule{
+			"GET /team/get": {RPS: 0.001, Burst: 1},
+		},
+	})
+	mockService := handlers.NewMockService(ctrl)
+	router, err := NewRouter("0", handlers.NewHandler(mockService, slog.Default()),
+		WithRateLimit(limiter),
+		WithMaxBodyBytes(64),
+	)
+	require.NoError(t, err)
+
+	t.Run("body over the limit", func(t *testing.T) {
+		body := `{"pull_request_id": "pr-1", "pull_request_name": "` + strings.Repeat("x", 128) + `", "author_id": "u1"}`
+		rec := httptest.NewRecorder()
+		router.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		mockService.EXPECT().
+			GetTeamWithMembers(gomock.Any(), "backend").
+			Return(&models.Team{TeamName: "backend", Members: []models.TeamMember{}}, nil)
+
+		rec := httptest.NewRecorder()
+		router.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = httptest.NewRecorder()
+		router.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil))
+		require.Equal(t, http.StatusTooManyRequests, rec.Code)
+		require.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Equal(t, models.RateLimitedErrorCode, resp.Error.Code)
+	})
+}
+
+func TestRouterSCIM(t *testing.T) {
+	scimHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	router, err := NewRouter("0", handlers.NewHandler(nil, slog.Default()), WithSCIM(scimHandler))
+	require.NoError(t, err)
+
+	// the body is not one of the spec, the validation must let it through
+	req := httptest.NewRequest(http.MethodPost, "/scim/v2/Users", strings.NewReader(`{"userName": "u1"}`))
+	req.Header.Set("Content-Type", "application/scim+json")
+	rec := httptest.NewRecorder()
+	router.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+}
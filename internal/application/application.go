@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,45 +13,71 @@ import (
 
 	"github.com/Sugyk/avito_test_task/internal/api"
 	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/codehost"
+	"github.com/Sugyk/avito_test_task/internal/config"
+	"github.com/Sugyk/avito_test_task/internal/gql"
+	"github.com/Sugyk/avito_test_task/internal/grpcapi"
+	"github.com/Sugyk/avito_test_task/internal/health"
+	"github.com/Sugyk/avito_test_task/internal/idempotency"
+	"github.com/Sugyk/avito_test_task/internal/metrics"
+	"github.com/Sugyk/avito_test_task/internal/migrations"
+	"github.com/Sugyk/avito_test_task/internal/ratelimit"
 	"github.com/Sugyk/avito_test_task/internal/repository"
+	"github.com/Sugyk/avito_test_task/internal/scim"
 	"github.com/Sugyk/avito_test_task/internal/service"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
 	"github.com/Sugyk/avito_test_task/pkg/database"
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/jmoiron/sqlx"
 )
 
 type Application struct {
-	db      *sqlx.DB
-	logger  *slog.Logger
-	repo    *repository.Repository
-	service *service.Service
-	router  *api.Router
+	db       *sqlx.DB
+	logger   *slog.Logger
+	metrics  *metrics.Metrics
+	health   *health.Checker
+	repo     *repository.Repository
+	codeHost *codehost.AsyncClient
+	service  *service.Service
+	router   *api.Router
+	admin    *api.AdminRouter
+	grpc     *grpcapi.Server
+
+	shutdownTracing func(context.Context) error
+	// stopJobs stops the background jobs
+	stopJobs context.CancelFunc
 
 	wg      sync.WaitGroup
 	errChan chan error
 
-	listening_port string
+	config *config.Config
 }
 
-func NewApplication() *Application {
+func NewApplication(cfg *config.Config) *Application {
 	return &Application{
+		config:  cfg,
 		errChan: make(chan error),
 	}
 }
 
 func (a *Application) Start(ctx context.Context) error {
-	if err := a.initConfig(); err != nil {
-		return fmt.Errorf("init config: %w", err)
-	}
 	if err := a.initLogger(); err != nil {
 		return fmt.Errorf("init logger: %w", err)
 	}
+	if err := a.initTracing(ctx); err != nil {
+		return fmt.Errorf("init tracing: %w", err)
+	}
+	if err := a.initMetrics(); err != nil {
+		return fmt.Errorf("init metrics: %w", err)
+	}
 	if err := a.initDatabase(ctx); err != nil {
 		return fmt.Errorf("init database: %w", err)
 	}
 	if err := a.initRepository(); err != nil {
 		return fmt.Errorf("init repository: %w", err)
 	}
+	if err := a.initCodeHost(); err != nil {
+		return fmt.Errorf("init code host: %w", err)
+	}
 	if err := a.initService(); err != nil {
 		return fmt.Errorf("init service: %w", err)
 	}
@@ -63,41 +90,98 @@ func (a *Application) Start(ctx context.Context) error {
 	}
 
 	a.startHTTPServer()
+	a.startJobs()
 
+	a.health.SetStarted()
 	a.logger.Info("application started successfully")
 	return nil
 
 }
 
-func (a *Application) initConfig() error {
-	a.listening_port = os.Getenv("LISTEN_PORT")
-	if a.listening_port == "" {
-		return fmt.Errorf("parsing LISTEN_PORT error: listen port is missing")
+func (a *Application) initLogger() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(a.config.Log.Level)); err != nil {
+		return err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, opts)
+	if a.config.Log.Format == "text" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
+	a.logger = slog.New(handler)
 	return nil
 }
 
-func (a *Application) initLogger() error {
-	a.logger = slog.New(
-		slog.NewJSONHandler(os.Stdout, nil),
-	)
+func (a *Application) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Setup(ctx)
+	if err != nil {
+		return err
+	}
+	a.shutdownTracing = shutdown
+	return nil
+}
+
+func (a *Application) initMetrics() error {
+	a.metrics = metrics.New()
 	return nil
 }
 
 func (a *Application) initDatabase(ctx context.Context) error {
-	db, err := database.NewDbConnection(ctx)
+	db, err := database.NewDbConnection(ctx, DatabaseConfig(a.config), a.logger)
 	if err != nil {
 		return fmt.Errorf("open database: %w", err)
 	}
 	a.db = db
+	a.metrics.RegisterDB(db.DB)
 
 	a.logger.Info("database connection established")
 	return nil
 }
 
+// DatabaseConfig converts the database settings of cfg for pkg/database.
+func DatabaseConfig(cfg *config.Config) database.Config {
+	db := cfg.Database
+	return database.Config{
+		Username:        db.Username,
+		Password:        db.Password,
+		Address:         db.Address,
+		Port:            db.Port,
+		Name:            db.Name,
+		SSLMode:         db.SSLMode,
+		MaxConns:        int32(db.MaxConns),
+		MinConns:        int32(db.MinConns),
+		MaxConnLifetime: db.MaxConnLifetime,
+		MaxConnIdleTime: db.MaxConnIdleTime,
+
+		StatementTimeout: db.StatementTimeout,
+		ConnectTimeout:   db.ConnectTimeout,
+		StartupTimeout:   db.StartupTimeout,
+	}
+}
+
+// MigrationsFS returns the migrations the service was configured with.
+func MigrationsFS(cfg *config.Config) fs.FS {
+	return database.MigrationsFS(cfg.Migrations.Path, migrations.FS)
+}
+
 func (a *Application) migrate() error {
-	if err := database.RunMigrations(a.db); err != nil {
-		if err == migrate.ErrNoChange {
+	if !a.config.Migrations.Auto {
+		a.logger.Info("auto-migration disabled")
+		return nil
+	}
+	migrator, err := database.NewMigrator(DatabaseConfig(a.config), MigrationsFS(a.config))
+	if err != nil {
+		return fmt.Errorf("open migrator: %w", err)
+	}
+	defer func() {
+		if err := migrator.Close(); err != nil {
+			a.logger.Error("migrator closed with error", "error", err)
+		}
+	}()
+
+	if err := migrator.Up(); err != nil {
+		if errors.Is(err, database.ErrNoChange) {
 			a.logger.Info("no changes to migrate")
 			return nil
 		}
@@ -115,9 +199,29 @@ func (a *Application) initRepository() error {
 	return nil
 }
 
+func (a *Application) initCodeHost() error {
+	var client codehost.CodeHostClient = codehost.NopClient{}
+	if a.config.GitHub.Token != "" {
+		client = codehost.NewGitHubClient(
+			a.config.GitHub.APIURL,
+			a.config.GitHub.Token,
+			a.config.GitHub.Repository,
+		)
+		a.logger.Info("reviewers sync with GitHub enabled")
+	}
+	a.codeHost = codehost.NewAsyncClient(
+		client,
+		a.logger,
+		codehost.DefaultAsyncConfig(),
+	)
+	return nil
+}
+
 func (a *Application) initService() error {
 	a.service = service.NewService(
 		a.repo,
+		a.codeHost,
+		a.metrics,
 		a.logger,
 	)
 	return nil
@@ -129,24 +233,188 @@ func (a *Application) initRouter() error {
 		a.logger,
 	)
 
-	a.router = api.NewRouter(
-		a.listening_port,
+	opts := []api.RouterOption{
+		api.WithMetrics(a.metrics),
+		api.WithRequestLogging(a.logger),
+		api.WithTimeouts(api.Timeouts{
+			Read:       a.config.HTTP.ReadTimeout,
+			ReadHeader: a.config.HTTP.ReadHeaderTimeout,
+			Write:      a.config.HTTP.WriteTimeout,
+			Idle:       a.config.HTTP.IdleTimeout,
+		}),
+	}
+	if a.config.Features.GraphQL {
+		graphqlHandler, err := gql.NewHandler(
+			a.service,
+			a.logger,
+		)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, api.WithGraphQL(graphqlHandler))
+	}
+	if a.config.Features.Tracing {
+		opts = append(opts, api.WithTracing())
+	}
+	// the limiter also tells clients apart for idempotency keys, so it is
+	// built even when rate limiting is off
+	limits := a.config.Limits
+	routes := make(map[string]ratelimit.Rule, len(limits.RateLimit.Routes))
+	for route, rule := range limits.RateLimit.Routes {
+		routes[route] = ratelimit.Rule{RPS: rule.RPS, Burst: rule.Burst}
+	}
+	limiter := ratelimit.New(ratelimit.Config{
+		Default:    ratelimit.Rule{RPS: limits.RateLimit.Default.RPS, Burst: limits.RateLimit.Default.Burst},
+		Routes:     routes,
+		TrustProxy: limits.RateLimit.TrustProxy,
+		Tokens:     limits.RateLimit.Tokens,
+	})
+	if limits.RateLimit.Enabled {
+		opts = append(opts, api.WithRateLimit(limiter))
+	}
+	if a.config.Limits.MaxBodyBytes > 0 {
+		opts = append(opts, api.WithMaxBodyBytes(a.config.Limits.MaxBodyBytes))
+	}
+	if a.config.Idempotency.Enabled {
+		opts = append(opts, api.WithIdempotency(a.repo, limiter.ClientKey, a.logger))
+	}
+	if !a.config.Features.Docs {
+		opts = append(opts, api.WithoutDocs())
+	}
+	if a.config.SCIM.Enabled {
+		opts = append(opts, api.WithSCIM(scim.NewHandler(a.service, a.config.SCIM.Token, a.logger)))
+	}
+
+	router, err := api.NewRouter(
+		a.config.HTTP.Port,
 		handler,
+		opts...,
 	)
+	if err != nil {
+		return err
+	}
+	a.router = router
+
+	a.health = health.NewChecker(2 * time.Second)
+	a.health.AddCheck("database", func(ctx context.Context) error {
+		return database.Ping(ctx, a.db)
+	})
+	latest, err := database.LatestMigration(MigrationsFS(a.config))
+	if err != nil {
+		return fmt.Errorf("read migrations: %w", err)
+	}
+	a.health.AddCheck("migrations", func(ctx context.Context) error {
+		version, dirty, err := database.MigrationVersion(ctx, a.db)
+		if err != nil {
+			return fmt.Errorf("read migration version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("dirty migration version %d", version)
+		}
+		if version < latest {
+			return fmt.Errorf("schema version %d is behind latest migration %d", version, latest)
+		}
+		return nil
+	})
+
+	var adminOpts []api.AdminOption
+	if a.config.HTTP.AdminToken != "" {
+		adminOpts = append(adminOpts, api.WithDataTransfer(handler, a.config.HTTP.AdminToken))
+	}
+	a.admin = api.NewAdminRouter(
+		a.config.HTTP.AdminPort,
+		a.metrics.Handler(),
+		a.health,
+		adminOpts...,
+	)
+
+	if a.config.HTTP.GRPCPort != "" {
+		a.grpc = grpcapi.NewServer(
+			a.config.HTTP.GRPCPort,
+			a.service,
+			a.logger,
+		)
+	}
 	return nil
 }
 
+func (a *Application) startJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopJobs = cancel
+
+	if a.config.Idempotency.Enabled {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			idempotency.RunCleanup(
+				ctx,
+				a.repo,
+				a.config.Idempotency.TTL,
+				a.config.Idempotency.CleanupInterval,
+				a.logger,
+			)
+		}()
+	}
+
+	jobs := &scheduler{
+		lock: func(ctx context.Context, key int64) (func(), bool, error) {
+			return database.TryAdvisoryLock(ctx, a.db, key)
+		},
+		logger: a.logger,
+	}
+	if a.config.Scheduler.StaleReviews {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			jobs.run(ctx, scheduledJob{
+				name:     "stale_reviews",
+				interval: a.config.Scheduler.StaleReviewsInterval,
+				lockKey:  staleReviewsLockKey,
+				run: func(ctx context.Context) error {
+					_, err := a.service.ReassignStaleReviews(ctx)
+					return err
+				},
+			})
+		}()
+	}
+}
+
 func (a *Application) startHTTPServer() {
 	a.wg.Add(1)
 
 	go func() {
 		defer a.wg.Done()
 
-		a.logger.Info("Starting HTTP server on " + a.listening_port)
+		a.logger.Info("Starting HTTP server on " + a.config.HTTP.Port)
 		if err := a.router.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			a.errChan <- fmt.Errorf("HTTP server error: %w", err)
 		}
 	}()
+
+	a.wg.Add(1)
+
+	go func() {
+		defer a.wg.Done()
+
+		a.logger.Info("Starting admin HTTP server on " + a.config.HTTP.AdminPort)
+		if err := a.admin.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.errChan <- fmt.Errorf("admin HTTP server error: %w", err)
+		}
+	}()
+
+	if a.grpc == nil {
+		return
+	}
+	a.wg.Add(1)
+
+	go func() {
+		defer a.wg.Done()
+
+		a.logger.Info("Starting gRPC server on " + a.config.HTTP.GRPCPort)
+		if err := a.grpc.Start(); err != nil {
+			a.errChan <- fmt.Errorf("gRPC server error: %w", err)
+		}
+	}()
 }
 
 func (a *Application) Wait(ctx context.Context, cancel context.CancelFunc) error {
@@ -160,13 +428,31 @@ func (a *Application) Wait(ctx context.Context, cancel context.CancelFunc) error
 		return err
 	}
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	a.health.SetShuttingDown()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), a.config.HTTP.ShutdownTimeout)
 	defer shutdownCancel()
 
 	if err := a.router.Shutdown(shutdownCtx); err != nil {
 		a.logger.Error("HTTP server shutdown error", "error", err)
 	}
 
+	if err := a.admin.Shutdown(shutdownCtx); err != nil {
+		a.logger.Error("admin HTTP server shutdown error", "error", err)
+	}
+
+	if a.grpc != nil {
+		if err := a.grpc.Shutdown(shutdownCtx); err != nil {
+			a.logger.Error("gRPC server shutdown error", "error", err)
+		}
+	}
+
+	a.stopJobs()
+
+	if err := a.codeHost.Close(shutdownCtx); err != nil {
+		a.logger.Error("code host sync stopped with pending jobs", "error", err)
+	}
+
 	if err := a.db.Close(); err != nil {
 		a.logger.Error("database closed with error", "error", err)
 	} else {
@@ -175,6 +461,10 @@ func (a *Application) Wait(ctx context.Context, cancel context.CancelFunc) error
 
 	a.wg.Wait()
 
+	if err := a.shutdownTracing(shutdownCtx); err != nil {
+		a.logger.Error("tracing shutdown error", "error", err)
+	}
+
 	a.logger.Info("graceful shutdown completed")
 
 	return nil
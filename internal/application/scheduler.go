@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Advisory lock keys of the scheduled jobs. They only have to differ from
+// each other and from other users of advisory locks in the database.
+const (
+	staleReviewsLockKey int64 = 0x5052_0001
+)
+
+// lockFunc tries to take the lock key, see database.TryAdvisoryLock.
+type lockFunc func(ctx context.Context, key int64) (unlock func(), ok bool, err error)
+
+// scheduledJob is run every interval by the replica that wins its lock, so
+// that replicas never run it at the same time.
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	lockKey  int64
+	run      func(ctx context.Context) error
+}
+
+type scheduler struct {
+	lock   lockFunc
+	logger *slog.Logger
+}
+
+// run runs job every interval until ctx is done. Runs the lock is held
+// elsewhere for are skipped.
+func (s *scheduler) run(ctx context.Context, job scheduledJob) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.runOnce(ctx, job)
+	}
+}
+
+func (s *scheduler) runOnce(ctx context.Context, job scheduledJob) {
+	logger := s.logger.With("job", job.name)
+
+	unlock, ok, err := s.lock(ctx, job.lockKey)
+	if err != nil {
+		logger.Error("scheduler: can not take the job lock", "error", err.Error())
+		return
+	}
+	if !ok {
+		logger.Debug("scheduler: job runs on another replica")
+		return
+	}
+	defer unlock()
+
+	start := time.Now()
+	if err := job.run(ctx); err != nil {
+		logger.Error("scheduler: job failed", "error", err.Error(), "duration", time.Since(start))
+		return
+	}
+	logger.Debug("scheduler: job done", "duration", time.Since(start))
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLocks is a lockFunc shared by several schedulers, standing in for
+// the advisory locks of a shared database.
+type memoryLocks struct {
+	mu   sync.Mutex
+	held map[int64]bool
+	err  error
+}
+
+func (l *memoryLocks) lock(_ context.Context, key int64) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, true, nil
+}
+
+func newTestScheduler(locks *memoryLocks) *scheduler {
+	return &scheduler{lock: locks.lock, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func TestScheduler_SkipsRunWhileLockIsHeldElsewhere(t *testing.T) {
+	locks := &memoryLocks{held: map[int64]bool{}}
+	leader, follower := newTestScheduler(locks), newTestScheduler(locks)
+
+	runs := 0
+	release := make(chan struct{})
+	started := make(chan struct{})
+	job := scheduledJob{name: "test", lockKey: 1, run: func(context.Context) error {
+		runs++
+		if runs == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		leader.runOnce(context.Background(), job)
+		close(done)
+	}()
+	<-started
+	follower.runOnce(context.Background(), job)
+	close(release)
+	<-done
+	require.Equal(t, 1, runs)
+
+	// the lock is released after the run
+	follower.runOnce(context.Background(), job)
+	require.Equal(t, 2, runs)
+}
+
+func TestScheduler_ReleasesLockOnJobError(t *testing.T) {
+	locks := &memoryLocks{held: map[int64]bool{}}
+	s := newTestScheduler(locks)
+
+	s.runOnce(context.Background(), scheduledJob{name: "test", lockKey: 1, run: func(context.Context) error {
+		return errors.New("boom")
+	}})
+	require.Empty(t, locks.held)
+}
+
+func TestScheduler_LockError(t *testing.T) {
+	locks := &memoryLocks{held: map[int64]bool{}, err: errors.New("connection refused")}
+	s := newTestScheduler(locks)
+
+	s.runOnce(context.Background(), scheduledJob{name: "test", lockKey: 1, run: func(context.Context) error {
+		t.Fatal("job must not run without the lock")
+		return nil
+	}})
+}
+
+func TestScheduler_RunsEveryInterval(t *testing.T) {
+	locks := &memoryLocks{held: map[int64]bool{}}
+	s := newTestScheduler(locks)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx, scheduledJob{name: "test", interval: time.Millisecond, lockKey: 1, run: func(context.Context) error {
+			select {
+			case runs <- struct{}{}:
+			default:
+			}
+			return nil
+		}})
+		close(done)
+	}()
+	for range 3 {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+	cancel()
+	<-done
+}
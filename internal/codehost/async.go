@@ -0,0 +1,178 @@
+package codehost
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type operation int
+
+const (
+	opRequest operation = iota
+	opRemove
+)
+
+type job struct {
+	op        operation
+	prID      string
+	reviewers []string
+}
+
+type AsyncConfig struct {
+	QueueSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultAsyncConfig() AsyncConfig {
+	return AsyncConfig{
+		QueueSize:   256,
+		MaxAttempts: 5,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// AsyncClient queues reviewer updates and delivers them to the wrapped client
+// in the background, retrying transient failures with exponential backoff.
+type AsyncClient struct {
+	client CodeHostClient
+	logger *slog.Logger
+	cfg    AsyncConfig
+
+	jobs   chan job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAsyncClient(client CodeHostClient, logger *slog.Logger, cfg AsyncConfig) *AsyncClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &AsyncClient{
+		client: client,
+		logger: logger,
+		cfg:    cfg,
+		jobs:   make(chan job, cfg.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	a.wg.Add(1)
+	go a.run()
+	return a
+}
+
+// CheckPullRequestID checks prID with the wrapped client right away.
+func (a *AsyncClient) CheckPullRequestID(prID string) error {
+	return a.client.CheckPullRequestID(prID)
+}
+
+func (a *AsyncClient) RequestReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return a.enqueue(job{op: opRequest, prID: prID, reviewers: reviewers})
+}
+
+func (a *AsyncClient) RemoveReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return a.enqueue(job{op: opRemove, prID: prID, reviewers: reviewers})
+}
+
+func (a *AsyncClient) enqueue(j job) error {
+	if len(j.reviewers) == 0 {
+		return nil
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrClientClosed
+	}
+	select {
+	case a.jobs <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting new jobs and waits until the queued ones are delivered
+// or ctx expires, in which case pending jobs are dropped.
+func (a *AsyncClient) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.jobs)
+	}
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		a.cancel()
+		return nil
+	case <-ctx.Done():
+		a.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (a *AsyncClient) run() {
+	defer a.wg.Done()
+	for j := range a.jobs {
+		if err := a.deliver(j); err != nil {
+			a.logger.Error("code host: reviewer sync failed",
+				"pr_id", j.prID,
+				"reviewers", j.reviewers,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (a *AsyncClient) deliver(j job) error {
+	backoff := a.cfg.BaseBackoff
+	var err error
+	for attempt := 1; attempt <= a.cfg.MaxAttempts; attempt++ {
+		switch j.op {
+		case opRequest:
+			err = a.client.RequestReviewers(a.ctx, j.prID, j.reviewers)
+		case opRemove:
+			err = a.client.RemoveReviewers(a.ctx, j.prID, j.reviewers)
+		}
+		if err == nil || !retryable(err) || attempt == a.cfg.MaxAttempts {
+			return err
+		}
+		a.logger.Warn("code host: retrying reviewer sync",
+			"pr_id", j.prID,
+			"attempt", attempt,
+			"error", err.Error(),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-a.ctx.Done():
+			return a.ctx.Err()
+		}
+		backoff = min(backoff*2, a.cfg.MaxBackoff)
+	}
+	return err
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, ErrUnknownPullRequest) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Retryable()
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package codehost
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnknownPullRequest = errors.New("pull request can not be mapped to the code host")
+	ErrQueueFull          = errors.New("code host sync queue is full")
+	ErrClientClosed       = errors.New("code host client is closed")
+)
+
+// CodeHostClient mirrors reviewer assignments to the code host the pull
+// request lives on. Reviewers are passed as user ids, which are expected to
+// match the logins on the code host. CheckPullRequestID tells whether prID
+// names a pull request of the code host, failing with ErrUnknownPullRequest
+// when it does not.
+type CodeHostClient interface {
+	CheckPullRequestID(prID string) error
+	RequestReviewers(ctx context.Context, prID string, reviewers []string) error
+	RemoveReviewers(ctx context.Context, prID string, reviewers []string) error
+}
+
+// NopClient is used when no code host is configured.
+type NopClient struct{}
+
+func (NopClient) CheckPullRequestID(prID string) error {
+	return nil
+}
+
+func (NopClient) RequestReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return nil
+}
+
+func (NopClient) RemoveReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return nil
+}
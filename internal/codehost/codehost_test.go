@@ -0,0 +1,92 @@
+package codehost
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/codehost/codehosttest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubClient_RequestAndRemoveReviewers(t *testing.T) {
+	fake := codehosttest.NewFakeGitHub()
+	defer fake.Close()
+
+	client := NewGitHubClient(fake.URL, "token", "acme/api")
+
+	require.NoError(t, client.RequestReviewers(context.Background(), "42", []string{"alice", "bob"}))
+	require.Equal(t, []string{"alice", "bob"}, fake.Reviewers("acme/api#42"))
+
+	require.NoError(t, client.RemoveReviewers(context.Background(), "acme/api#42", []string{"alice"}))
+	require.Equal(t, []string{"bob"}, fake.Reviewers("acme/api#42"))
+}
+
+func TestGitHubClient_UnknownPullRequest(t *testing.T) {
+	client := NewGitHubClient("http://unused", "", "")
+
+	tests := []string{"pr-1001", "42", "acme#42", "acme/api#x"}
+	for _, prID := range tests {
+		t.Run(prID, func(t *testing.T) {
+			err := client.RequestReviewers(context.Background(), prID, []string{"alice"})
+			require.ErrorIs(t, err, ErrUnknownPullRequest)
+			require.ErrorIs(t, client.CheckPullRequestID(prID), ErrUnknownPullRequest)
+		})
+	}
+}
+
+func TestGitHubClient_CheckPullRequestID(t *testing.T) {
+	client := NewGitHubClient("http://unused", "", "acme/api")
+
+	require.NoError(t, client.CheckPullRequestID("42"))
+	require.NoError(t, client.CheckPullRequestID("#42"))
+	require.NoError(t, client.CheckPullRequestID("acme/web#7"))
+	require.ErrorIs(t, client.CheckPullRequestID("pr-1001"), ErrUnknownPullRequest)
+	require.NoError(t, NopClient{}.CheckPullRequestID("pr-1001"))
+}
+
+func TestGitHubClient_StatusError(t *testing.T) {
+	fake := codehosttest.NewFakeGitHub()
+	defer fake.Close()
+	fake.FailNext(1)
+
+	client := NewGitHubClient(fake.URL, "", "acme/api")
+	err := client.RequestReviewers(context.Background(), "7", []string{"alice"})
+
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.True(t, statusErr.Retryable())
+}
+
+func TestAsyncClient_RetriesTransientErrors(t *testing.T) {
+	fake := codehosttest.NewFakeGitHub()
+	defer fake.Close()
+	fake.FailNext(2)
+
+	cfg := AsyncConfig{QueueSize: 4, MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	async := NewAsyncClient(
+		NewGitHubClient(fake.URL, "", "acme/api"),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cfg,
+	)
+
+	require.NoError(t, async.RequestReviewers(context.Background(), "1", []string{"alice"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, async.Close(ctx))
+
+	require.Equal(t, 3, fake.Calls())
+	require.Equal(t, []string{"alice"}, fake.Reviewers("acme/api#1"))
+}
+
+func TestAsyncClient_ClosedRejectsJobs(t *testing.T) {
+	async := NewAsyncClient(NopClient{}, slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultAsyncConfig())
+	require.NoError(t, async.Close(context.Background()))
+
+	err := async.RequestReviewers(context.Background(), "1", []string{"alice"})
+	require.ErrorIs(t, err, ErrClientClosed)
+}
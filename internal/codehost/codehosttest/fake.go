@@ -0,0 +1,93 @@
+// Package codehosttest provides a fake code host for tests.
+package codehosttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+)
+
+// FakeGitHub is an in-memory stand-in for the GitHub requested_reviewers API
+// served by httptest.
+type FakeGitHub struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	reviewers map[string][]string
+	calls     int
+	failures  int
+}
+
+func NewFakeGitHub() *FakeGitHub {
+	f := &FakeGitHub{
+		reviewers: make(map[string][]string),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls/{number}/requested_reviewers", f.handle)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/pulls/{number}/requested_reviewers", f.handle)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+// FailNext makes the next n requests fail with 503 Service Unavailable.
+func (f *FakeGitHub) FailNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = n
+}
+
+// Reviewers returns the reviewers requested on "owner/repo#number".
+func (f *FakeGitHub) Reviewers(prID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.reviewers[prID])
+}
+
+// Calls returns the number of requests received, including failed ones.
+func (f *FakeGitHub) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *FakeGitHub) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var req struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	key := r.PathValue("owner") + "/" + r.PathValue("repo") + "#" + r.PathValue("number")
+	current := f.reviewers[key]
+	for _, reviewer := range req.Reviewers {
+		switch r.Method {
+		case http.MethodPost:
+			if !slices.Contains(current, reviewer) {
+				current = append(current, reviewer)
+			}
+		case http.MethodDelete:
+			current = slices.DeleteFunc(current, func(s string) bool { return s == reviewer })
+		}
+	}
+	f.reviewers[key] = current
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusCreated)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"requested_reviewers": current})
+}
@@ -0,0 +1,124 @@
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// StatusError is returned when the code host answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("code host responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if sent again.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+type GitHubClient struct {
+	baseURL     string
+	token       string
+	defaultRepo string
+	httpClient  *http.Client
+}
+
+// NewGitHubClient creates a client for the GitHub REST API. Pull request ids
+// are expected in the "owner/repo#number" form; when defaultRepo is set a bare
+// "number" or "#number" is accepted as well.
+func NewGitHubClient(baseURL, token, defaultRepo string) *GitHubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	return &GitHubClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		token:       token,
+		defaultRepo: defaultRepo,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *GitHubClient) CheckPullRequestID(prID string) error {
+	_, _, err := c.parsePullRequestID(prID)
+	return err
+}
+
+func (c *GitHubClient) RequestReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return c.doReviewers(ctx, http.MethodPost, prID, reviewers)
+}
+
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, prID string, reviewers []string) error {
+	return c.doReviewers(ctx, http.MethodDelete, prID, reviewers)
+}
+
+func (c *GitHubClient) doReviewers(ctx context.Context, method, prID string, reviewers []string) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	repo, number, err := c.parsePullRequestID(prID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Reviewers []string `json:"reviewers"`
+	}{Reviewers: reviewers})
+	if err != nil {
+		return fmt.Errorf("encode reviewers: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, repo, number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return nil
+}
+
+func (c *GitHubClient) parsePullRequestID(prID string) (string, int, error) {
+	repo, num, found := strings.Cut(prID, "#")
+	if !found {
+		repo, num = "", prID
+	}
+	if repo == "" {
+		repo = c.defaultRepo
+	}
+	if strings.Count(repo, "/") != 1 {
+		return "", 0, fmt.Errorf("%w: %q", ErrUnknownPullRequest, prID)
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("%w: %q", ErrUnknownPullRequest, prID)
+	}
+	return repo, number, nil
+}
@@ -0,0 +1,490 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+type Config struct {
+	HTTP        HTTPConfig        `yaml:"http"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Log         LogConfig         `yaml:"log"`
+	Migrations  MigrationsConfig  `yaml:"migrations"`
+	GitHub      GitHubConfig      `yaml:"github"`
+	Limits      LimitsConfig      `yaml:"limits"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Features    FeaturesConfig    `yaml:"features"`
+	SCIM        SCIMConfig        `yaml:"scim"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler"`
+}
+
+type HTTPConfig struct {
+	Port      string `yaml:"port"`
+	AdminPort string `yaml:"admin_port"`
+	// AdminToken is the bearer token of the admin import and export. They
+	// are not served when it is empty.
+	AdminToken        string        `yaml:"admin_token"`
+	GRPCPort          string        `yaml:"grpc_port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+}
+
+type DatabaseConfig struct {
+	Username        string        `yaml:"username"`
+	Password        string        `yaml:"password"`
+	Address         string        `yaml:"address"`
+	Port            string        `yaml:"port"`
+	Name            string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`
+	MaxConns        int           `yaml:"max_conns"`
+	MinConns        int           `yaml:"min_conns"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+	// StatementTimeout of zero disables the timeout.
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+	ConnectTimeout   time.Duration `yaml:"connect_timeout"`
+	// StartupTimeout is how long to wait for the database at start.
+	StartupTimeout time.Duration `yaml:"startup_timeout"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+type MigrationsConfig struct {
+	// Path is a directory with migrations, the embedded ones are used when empty.
+	Path string `yaml:"path"`
+	// Auto applies pending migrations at start.
+	Auto bool `yaml:"auto"`
+}
+
+type GitHubConfig struct {
+	APIURL     string `yaml:"api_url"`
+	Token      string `yaml:"token"`
+	Repository string `yaml:"repository"`
+}
+
+type LimitsConfig struct {
+	// MaxBodyBytes caps request bodies, zero disables the cap.
+	MaxBodyBytes int64           `yaml:"max_body_bytes"`
+	RateLimit    RateLimitConfig `yaml:"rate_limit"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// TrustProxy keys clients without a known token by X-Forwarded-For.
+	TrustProxy bool     `yaml:"trust_proxy"`
+	Default    RateRule `yaml:"default"`
+	// Routes overrides Default per route, keyed by "METHOD /path".
+	Routes map[string]RateRule `yaml:"routes"`
+	// Tokens lists the API tokens clients are keyed by; clients sending any
+	// other token are keyed by address.
+	Tokens []string `yaml:"tokens"`
+}
+
+// RateRule allows RPS requests per second per client, with bursts of up to
+// Burst requests.
+type RateRule struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
+}
+
+type IdempotencyConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// TTL is how long responses are kept for replay.
+	TTL             time.Duration `yaml:"ttl"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+type FeaturesConfig struct {
+	GraphQL bool `yaml:"graphql"`
+	Docs    bool `yaml:"docs"`
+	Tracing bool `yaml:"tracing"`
+}
+
+// SCIMConfig enables the SCIM endpoints used by the identity provider to
+// sync users and teams.
+type SCIMConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Token   string `yaml:"token"`
+}
+
+// SchedulerConfig enables the background jobs. Each job runs on one replica
+// at a time.
+type SchedulerConfig struct {
+	// StaleReviews reassigns reviews kept longer than the team's
+	// stale_review_hours.
+	StaleReviews         bool          `yaml:"stale_reviews"`
+	StaleReviewsInterval time.Duration `yaml:"stale_reviews_interval"`
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Port:              "8080",
+			AdminPort:         "8081",
+			GRPCPort:          "9090",
+			ReadTimeout:       10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Address: "localhost",
+			Port:    "5432",
+			Name:    "postgres",
+			SSLMode: "disable",
+			// pgxpool defaults, spelled out to make them visible in --print-config,
+			// except for MaxConns: pgxpool uses max(4, number of CPUs), the pool
+			// here does not grow with the host
+			MaxConns:        4,
+			MinConns:        0,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+
+			StatementTimeout: 30 * time.Second,
+			ConnectTimeout:   5 * time.Second,
+			StartupTimeout:   time.Minute,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Migrations: MigrationsConfig{
+			Auto: true,
+		},
+		Limits: LimitsConfig{
+			MaxBodyBytes: 1 << 20,
+			RateLimit: RateLimitConfig{
+				Enabled: true,
+				Default: RateRule{RPS: 50, Burst: 100},
+				Routes: map[string]RateRule{
+					"POST /pullRequest/create": {RPS: 10, Burst: 20},
+				},
+			},
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled:         true,
+			TTL:             24 * time.Hour,
+			CleanupInterval: time.Hour,
+		},
+		Features: FeaturesConfig{
+			GraphQL: true,
+			Docs:    true,
+			Tracing: true,
+		},
+		Scheduler: SchedulerConfig{
+			StaleReviews:         true,
+			StaleReviewsInterval: 5 * time.Minute,
+		},
+	}
+}
+
+// field binds one setting to its environment variable and command-line flag.
+type field struct {
+	flag string
+	env  string
+}
+
+// bind registers a flag for every setting of c on fs, so that the flag's
+// Value writes straight into c.
+func (c *Config) bind(fs *flag.FlagSet) []field {
+	var fields []field
+	str := func(p *string, name, env, usage string) {
+		fs.StringVar(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+	dur := func(p *time.Duration, name, env, usage string) {
+		fs.DurationVar(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+	num := func(p *int, name, env, usage string) {
+		fs.IntVar(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+	num64 := func(p *int64, name, env, usage string) {
+		fs.Int64Var(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+	float := func(p *float64, name, env, usage string) {
+		fs.Float64Var(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+	boolean := func(p *bool, name, env, usage string) {
+		fs.BoolVar(p, name, *p, usage)
+		fields = append(fields, field{flag: name, env: env})
+	}
+
+	str(&c.HTTP.Port, "http.port", "LISTEN_PORT", "port of the REST API")
+	str(&c.HTTP.AdminPort, "http.admin-port", "ADMIN_PORT", "port of the metrics and health endpoints")
+	str(&c.HTTP.AdminToken, "http.admin-token", "ADMIN_TOKEN", "bearer token of the admin import and export, disabled when empty")
+	str(&c.HTTP.GRPCPort, "http.grpc-port", "GRPC_PORT", "port of the gRPC API, disabled when set to empty")
+	dur(&c.HTTP.ReadTimeout, "http.read-timeout", "HTTP_READ_TIMEOUT", "maximum duration for reading a request")
+	dur(&c.HTTP.ReadHeaderTimeout, "http.read-header-timeout", "HTTP_READ_HEADER_TIMEOUT", "maximum duration for reading request headers")
+	dur(&c.HTTP.WriteTimeout, "http.write-timeout", "HTTP_WRITE_TIMEOUT", "maximum duration for writing a response")
+	dur(&c.HTTP.IdleTimeout, "http.idle-timeout", "HTTP_IDLE_TIMEOUT", "maximum keep-alive idle time")
+	dur(&c.HTTP.ShutdownTimeout, "http.shutdown-timeout", "SHUTDOWN_TIMEOUT", "graceful shutdown deadline")
+
+	str(&c.Database.Username, "db.username", "DB_USERNAME", "database user")
+	str(&c.Database.Password, "db.password", "DB_PASSWORD", "database password")
+	str(&c.Database.Address, "db.address", "DB_ADDRESS", "database host")
+	str(&c.Database.Port, "db.port", "DB_PORT", "database port")
+	str(&c.Database.Name, "db.name", "DB_NAME", "database name")
+	str(&c.Database.SSLMode, "db.sslmode", "DB_SSLMODE", "database sslmode")
+	num(&c.Database.MaxConns, "db.max-conns", "DB_MAX_CONNS", "maximum size of the connection pool")
+	num(&c.Database.MinConns, "db.min-conns", "DB_MIN_CONNS", "minimum size of the connection pool")
+	dur(&c.Database.MaxConnLifetime, "db.max-conn-lifetime", "DB_MAX_CONN_LIFETIME", "maximum lifetime of a connection")
+	dur(&c.Database.MaxConnIdleTime, "db.max-conn-idle-time", "DB_MAX_CONN_IDLE_TIME", "maximum idle time of a connection")
+	dur(&c.Database.StatementTimeout, "db.statement-timeout", "DB_STATEMENT_TIMEOUT", "abort statements running longer, 0 disables")
+	dur(&c.Database.ConnectTimeout, "db.connect-timeout", "DB_CONNECT_TIMEOUT", "timeout of a single connection attempt")
+	dur(&c.Database.StartupTimeout, "db.startup-timeout", "DB_STARTUP_TIMEOUT", "how long to retry reaching the database at start")
+
+	str(&c.Log.Level, "log.level", "LOG_LEVEL", "log level: debug, info, warn or error")
+	str(&c.Log.Format, "log.format", "LOG_FORMAT", "log format: json or text")
+
+	str(&c.Migrations.Path, "migrations.path", "MIGRATIONS_PATH", "directory with migrations, the embedded ones are used when empty")
+	boolean(&c.Migrations.Auto, "migrations.auto", "MIGRATIONS_AUTO", "apply pending migrations at start")
+
+	str(&c.GitHub.APIURL, "github.api-url", "GITHUB_API_URL", "GitHub API base URL")
+	str(&c.GitHub.Token, "github.token", "GITHUB_TOKEN", "GitHub token, reviewers sync is disabled when empty")
+	str(&c.GitHub.Repository, "github.repository", "GITHUB_REPOSITORY", "default owner/repo of pull requests")
+
+	num64(&c.Limits.MaxBodyBytes, "limits.max-body-bytes", "MAX_BODY_BYTES", "maximum request body size, 0 disables the cap")
+	boolean(&c.Limits.RateLimit.Enabled, "limits.rate-limit", "RATE_LIMIT_ENABLED", "throttle clients per route")
+	boolean(&c.Limits.RateLimit.TrustProxy, "limits.rate-limit-trust-proxy", "RATE_LIMIT_TRUST_PROXY", "key clients without a known token by X-Forwarded-For")
+	float(&c.Limits.RateLimit.Default.RPS, "limits.rate-limit-rps", "RATE_LIMIT_RPS", "default requests per second per client")
+	num(&c.Limits.RateLimit.Default.Burst, "limits.rate-limit-burst", "RATE_LIMIT_BURST", "default burst size per client")
+
+	boolean(&c.Idempotency.Enabled, "idempotency.enabled", "IDEMPOTENCY_ENABLED", "honor the Idempotency-Key header")
+	dur(&c.Idempotency.TTL, "idempotency.ttl", "IDEMPOTENCY_TTL", "how long responses are kept for replay")
+	dur(&c.Idempotency.CleanupInterval, "idempotency.cleanup-interval", "IDEMPOTENCY_CLEANUP_INTERVAL", "how often expired keys are deleted")
+
+	boolean(&c.Features.GraphQL, "features.graphql", "FEATURE_GRAPHQL", "serve POST /graphql")
+	boolean(&c.Features.Docs, "features.docs", "FEATURE_DOCS", "serve the OpenAPI spec and Swagger UI")
+	boolean(&c.Features.Tracing, "features.tracing", "FEATURE_TRACING", "trace HTTP requests")
+
+	boolean(&c.SCIM.Enabled, "scim.enabled", "SCIM_ENABLED", "serve the SCIM endpoints under /scim/v2/")
+	str(&c.SCIM.Token, "scim.token", "SCIM_TOKEN", "bearer token the identity provider authenticates with")
+
+	boolean(&c.Scheduler.StaleReviews, "scheduler.stale-reviews", "SCHEDULER_STALE_REVIEWS", "reassign reviews kept longer than the team allows")
+	dur(&c.Scheduler.StaleReviewsInterval, "scheduler.stale-reviews-interval", "SCHEDULER_STALE_REVIEWS_INTERVAL", "how often stale reviews are looked for")
+
+	return fields
+}
+
+// Command is what the binary was asked to do, besides the configuration.
+type Command struct {
+	// PrintConfig is set by --print-config.
+	PrintConfig bool
+	// Args are the positional arguments following the flags.
+	Args []string
+}
+
+// Load builds the configuration from, in increasing order of precedence,
+// defaults, the YAML file given by --config or CONFIG_FILE, environment
+// variables and command-line flags.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, Command, error) {
+	var cmd Command
+	cfg := new(Config)
+	*cfg = Default()
+
+	fs := flag.NewFlagSet("pr-manager", flag.ContinueOnError)
+	var path string
+	fs.StringVar(&path, "config", "", "path to a YAML config file")
+	fs.BoolVar(&cmd.PrintConfig, "print-config", false, "print the effective config, secrets redacted, and exit")
+	fields := cfg.bind(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, cmd, err
+	}
+	// parsing already wrote the flags into cfg; remember them and start over,
+	// so they can be applied last
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+	*cfg = Default()
+
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, cmd, err
+		}
+	}
+
+	for _, f := range fields {
+		if v, ok := lookupEnv(f.env); ok && v != "" {
+			if err := fs.Set(f.flag, v); err != nil {
+				return nil, cmd, fmt.Errorf("parsing %s: %w", f.env, err)
+			}
+		}
+	}
+	for name, v := range flags {
+		if name == "config" || name == "print-config" {
+			continue
+		}
+		if err := fs.Set(name, v); err != nil {
+			return nil, cmd, fmt.Errorf("parsing --%s: %w", name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, cmd, err
+	}
+	cmd.Args = fs.Args()
+	return cfg, cmd, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	if !validPort(c.HTTP.Port) {
+		errs = append(errs, fmt.Errorf("http.port: invalid port %q", c.HTTP.Port))
+	}
+	if !validPort(c.HTTP.AdminPort) {
+		errs = append(errs, fmt.Errorf("http.admin_port: invalid port %q", c.HTTP.AdminPort))
+	}
+	if c.HTTP.GRPCPort != "" && !validPort(c.HTTP.GRPCPort) {
+		errs = append(errs, fmt.Errorf("http.grpc_port: invalid port %q", c.HTTP.GRPCPort))
+	}
+	for name, d := range map[string]time.Duration{
+		"http.read_timeout":           c.HTTP.ReadTimeout,
+		"http.read_header_timeout":    c.HTTP.ReadHeaderTimeout,
+		"http.write_timeout":          c.HTTP.WriteTimeout,
+		"http.idle_timeout":           c.HTTP.IdleTimeout,
+		"database.max_conn_lifetime":  c.Database.MaxConnLifetime,
+		"database.max_conn_idle_time": c.Database.MaxConnIdleTime,
+		"database.statement_timeout":  c.Database.StatementTimeout,
+		"database.connect_timeout":    c.Database.ConnectTimeout,
+		"database.startup_timeout":    c.Database.StartupTimeout,
+	} {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", name))
+		}
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("http.shutdown_timeout: must be positive"))
+	}
+
+	if !validPort(c.Database.Port) {
+		errs = append(errs, fmt.Errorf("database.port: invalid port %q", c.Database.Port))
+	}
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("database.sslmode: unknown mode %q", c.Database.SSLMode))
+	}
+	if c.Database.MaxConns < 1 {
+		errs = append(errs, errors.New("database.max_conns: must be at least 1"))
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		errs = append(errs, errors.New("database.min_conns: must be between 0 and max_conns"))
+	}
+
+	if c.Limits.MaxBodyBytes < 0 {
+		errs = append(errs, errors.New("limits.max_body_bytes: must not be negative"))
+	}
+	rules := map[string]RateRule{"default": c.Limits.RateLimit.Default}
+	for route, rule := range c.Limits.RateLimit.Routes {
+		rules["routes."+route] = rule
+	}
+	for name, rule := range rules {
+		if rule.RPS < 0 || rule.Burst < 0 {
+			errs = append(errs, fmt.Errorf("limits.rate_limit.%s: rps and burst must not be negative", name))
+		}
+	}
+
+	if c.Idempotency.Enabled && (c.Idempotency.TTL <= 0 || c.Idempotency.CleanupInterval <= 0) {
+		errs = append(errs, errors.New("idempotency: ttl and cleanup_interval must be positive"))
+	}
+
+	if c.Scheduler.StaleReviews && c.Scheduler.StaleReviewsInterval <= 0 {
+		errs = append(errs, errors.New("scheduler.stale_reviews_interval: must be positive"))
+	}
+
+	if c.SCIM.Enabled && c.SCIM.Token == "" {
+		errs = append(errs, errors.New("scim.token: required when scim is enabled"))
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level: unknown level %q", c.Log.Level))
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("log.format: unknown format %q", c.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of c with secrets replaced by a placeholder.
+func (c Config) Redacted() Config {
+	if c.Database.Password != "" {
+		c.Database.Password = redacted
+	}
+	if c.GitHub.Token != "" {
+		c.GitHub.Token = redacted
+	}
+	if c.HTTP.AdminToken != "" {
+		c.HTTP.AdminToken = redacted
+	}
+	if c.SCIM.Token != "" {
+		c.SCIM.Token = redacted
+	}
+	if tokens := c.Limits.RateLimit.Tokens; len(tokens) > 0 {
+		c.Limits.RateLimit.Tokens = make([]string, len(tokens))
+		for i := range tokens {
+			c.Limits.RateLimit.Tokens[i] = redacted
+		}
+	}
+	return c
+}
+
+// Print writes c as YAML with secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
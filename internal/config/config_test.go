@@ -0,0 +1,132 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, cmd, err := Load(nil, env(nil))
+	require.NoError(t, err)
+	require.False(t, cmd.PrintConfig)
+	require.Empty(t, cmd.Args)
+	require.Equal(t, Default(), *cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, `
+http:
+  port: "7000"
+  write_timeout: 3s
+log:
+  level: warn
+  format: text
+database:
+  max_conns: 20
+`)
+	cfg, _, err := Load(
+		[]string{"--config", path, "--log.level", "debug"},
+		env(map[string]string{
+			"LISTEN_PORT": "7001",
+			"LOG_LEVEL":   "error",
+		}),
+	)
+	require.NoError(t, err)
+
+	// flag beats env beats file
+	require.Equal(t, "debug", cfg.Log.Level)
+	// env beats file
+	require.Equal(t, "7001", cfg.HTTP.Port)
+	// file beats defaults
+	require.Equal(t, 3*time.Second, cfg.HTTP.WriteTimeout)
+	require.Equal(t, "text", cfg.Log.Format)
+	require.Equal(t, 20, cfg.Database.MaxConns)
+	// untouched settings keep their defaults
+	require.Equal(t, "8081", cfg.HTTP.AdminPort)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeFile(t, "migrations:\n  auto: false\n")
+	cfg, _, err := Load(nil, env(map[string]string{"CONFIG_FILE": path}))
+	require.NoError(t, err)
+	require.False(t, cfg.Migrations.Auto)
+}
+
+func TestLoad_CommandArgs(t *testing.T) {
+	cfg, cmd, err := Load([]string{"--log.level", "warn", "migrate", "down", "1"}, env(nil))
+	require.NoError(t, err)
+	require.Equal(t, "warn", cfg.Log.Level)
+	require.Equal(t, []string{"migrate", "down", "1"}, cmd.Args)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		file string
+	}{
+		{name: "unknown flag", args: []string{"--nope"}},
+		{name: "bad env value", env: map[string]string{"DB_MAX_CONNS": "many"}},
+		{name: "unknown file key", file: "http:\n  prot: \"8080\"\n"},
+		{name: "invalid port", env: map[string]string{"LISTEN_PORT": "http"}},
+		{name: "min above max conns", args: []string{"--db.min-conns", "10", "--db.max-conns", "5"}},
+		{name: "unknown log format", env: map[string]string{"LOG_FORMAT": "xml"}},
+		{name: "scim without token", env: map[string]string{"SCIM_ENABLED": "true"}},
+		{name: "zero scheduler interval", env: map[string]string{"SCHEDULER_STALE_REVIEWS_INTERVAL": "0s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "--config", writeFile(t, tt.file))
+			}
+			_, _, err := Load(args, env(tt.env))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg, cmd, err := Load(
+		[]string{"--print-config"},
+		env(map[string]string{
+			"DB_PASSWORD":  "hunter2",
+			"GITHUB_TOKEN": "ghp_secret",
+			"SCIM_TOKEN":   "scim_secret",
+		}),
+	)
+	require.NoError(t, err)
+	require.True(t, cmd.PrintConfig)
+	cfg.Limits.RateLimit.Tokens = []string{"api_secret"}
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+	require.NotContains(t, buf.String(), "hunter2")
+	require.NotContains(t, buf.String(), "ghp_secret")
+	require.NotContains(t, buf.String(), "scim_secret")
+	require.NotContains(t, buf.String(), "api_secret")
+	require.Contains(t, buf.String(), redacted)
+	// printing does not modify the config itself
+	require.Equal(t, "hunter2", cfg.Database.Password)
+	require.Equal(t, []string{"api_secret"}, cfg.Limits.RateLimit.Tokens)
+}
@@ -0,0 +1,176 @@
+// Package dump encodes the state exported by the admin endpoints as a zip of
+// CSV files, one file per entity.
+package dump
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+const (
+	TeamsFile        = "teams.csv"
+	UsersFile        = "users.csv"
+	PullRequestsFile = "pull_requests.csv"
+	ReviewersFile    = "reviewers.csv"
+)
+
+var headers = map[string][]string{
+	TeamsFile:        {"team_name"},
+	UsersFile:        {"user_id", "username", "team_name", "is_active"},
+	PullRequestsFile: {"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at"},
+	ReviewersFile:    {"pull_request_id", "user_id"},
+}
+
+// WriteZip writes d to w as a zip with a CSV file per entity.
+func WriteZip(w io.Writer, d *models.Dump) error {
+	files := map[string][][]string{}
+	for _, team := range d.Teams {
+		files[TeamsFile] = append(files[TeamsFile], []string{team.TeamName})
+		for _, member := range team.Members {
+			isActive := member.IsActive != nil && *member.IsActive
+			files[UsersFile] = append(files[UsersFile], []string{
+				member.UserId, member.Username, team.TeamName, strconv.FormatBool(isActive),
+			})
+		}
+	}
+	for _, pr := range d.PullRequests {
+		files[PullRequestsFile] = append(files[PullRequestsFile], []string{
+			pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status),
+			optional(pr.CreatedAt), optional(pr.MergedAt),
+		})
+		for _, reviewer := range pr.AssignedReviewers {
+			files[ReviewersFile] = append(files[ReviewersFile], []string{pr.PullRequestId, reviewer})
+		}
+	}
+
+	zw := zip.NewWriter(w)
+	for _, name := range []string{TeamsFile, UsersFile, PullRequestsFile, ReviewersFile} {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(f)
+		if err := cw.Write(headers[name]); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(files[name]); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// ReadZip reads a zip written by WriteZip. Missing files are read as empty,
+// so a zip may hold, say, users.csv only; teams of users are added to the
+// dump when teams.csv does not list them.
+func ReadZip(data []byte) (*models.Dump, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read zip: %w", err)
+	}
+	records := map[string][][]string{}
+	for _, f := range zr.File {
+		if _, ok := headers[f.Name]; !ok {
+			return nil, fmt.Errorf("unexpected file %s, expected %s, %s, %s or %s",
+				f.Name, TeamsFile, UsersFile, PullRequestsFile, ReviewersFile)
+		}
+		rows, err := readCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+		records[f.Name] = rows
+	}
+
+	d := &models.Dump{Teams: []models.Team{}, PullRequests: []models.PullRequest{}}
+	teamIndex := map[string]int{}
+	addTeam := func(name string) int {
+		if i, ok := teamIndex[name]; ok {
+			return i
+		}
+		teamIndex[name] = len(d.Teams)
+		d.Teams = append(d.Teams, models.Team{TeamName: name, Members: []models.TeamMember{}})
+		return teamIndex[name]
+	}
+	for _, row := range records[TeamsFile] {
+		addTeam(row[0])
+	}
+	for line, row := range records[UsersFile] {
+		isActive, err := strconv.ParseBool(row[3])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: is_active: %w", UsersFile, line+2, err)
+		}
+		i := addTeam(row[2])
+		d.Teams[i].Members = append(d.Teams[i].Members, models.TeamMember{
+			UserId:   row[0],
+			Username: row[1],
+			IsActive: &isActive,
+		})
+	}
+
+	prIndex := map[string]int{}
+	for _, row := range records[PullRequestsFile] {
+		prIndex[row[0]] = len(d.PullRequests)
+		d.PullRequests = append(d.PullRequests, models.PullRequest{
+			PullRequestId:     row[0],
+			PullRequestName:   row[1],
+			AuthorId:          row[2],
+			Status:            models.Status(row[3]),
+			AssignedReviewers: []string{},
+			CreatedAt:         nullable(row[4]),
+			MergedAt:          nullable(row[5]),
+		})
+	}
+	for line, row := range records[ReviewersFile] {
+		i, ok := prIndex[row[0]]
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: pull request %q is not in %s", ReviewersFile, line+2, row[0], PullRequestsFile)
+		}
+		d.PullRequests[i].AssignedReviewers = append(d.PullRequests[i].AssignedReviewers, row[1])
+	}
+	return d, nil
+}
+
+// readCSV returns the rows of f after checking its header.
+func readCSV(f *zip.File) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	cr := csv.NewReader(rc)
+	cr.FieldsPerRecord = len(headers[f.Name])
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(header, headers[f.Name]) {
+		return nil, fmt.Errorf("header must be %v, got %v", headers[f.Name], header)
+	}
+	return cr.ReadAll()
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,100 @@
+package dump
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testDump() *models.Dump {
+	return &models.Dump{
+		Teams: []models.Team{
+			{TeamName: "backend", Members: []models.TeamMember{
+				{UserId: "u1", Username: "Alice", IsActive: ptr(true)},
+				{UserId: "u2", Username: "Bob, Jr.", IsActive: ptr(false)},
+			}},
+			{TeamName: "empty", Members: []models.TeamMember{}},
+		},
+		PullRequests: []models.PullRequest{
+			{
+				PullRequestId:     "pr-1",
+				PullRequestName:   "Add \"search\"",
+				AuthorId:          "u1",
+				Status:            models.StatusMerged,
+				AssignedReviewers: []string{"u2"},
+				CreatedAt:         ptr("2025-01-02T10:00:00Z"),
+				MergedAt:          ptr("2025-01-03T10:00:00Z"),
+			},
+			{
+				PullRequestId:     "pr-2",
+				PullRequestName:   "Fix login",
+				AuthorId:          "u2",
+				Status:            models.StatusOpen,
+				AssignedReviewers: []string{},
+			},
+		},
+	}
+}
+
+func TestZipRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(&buf, testDump()))
+
+	d, err := ReadZip(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, testDump(), d)
+}
+
+func writeFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestReadZip_UsersOnly(t *testing.T) {
+	d, err := ReadZip(writeFiles(t, map[string]string{
+		UsersFile: "user_id,username,team_name,is_active\nu1,Alice,backend,true\n",
+	}))
+	require.NoError(t, err)
+	require.Len(t, d.Teams, 1)
+	require.Equal(t, "backend", d.Teams[0].TeamName)
+	require.Equal(t, "u1", d.Teams[0].Members[0].UserId)
+	require.Empty(t, d.PullRequests)
+}
+
+func TestReadZip_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "unknown file", files: map[string]string{"teams.json": "[]"}},
+		{name: "wrong header", files: map[string]string{TeamsFile: "name\nbackend\n"}},
+		{name: "wrong column count", files: map[string]string{UsersFile: "user_id,username,team_name,is_active\nu1,Alice\n"}},
+		{name: "bad is_active", files: map[string]string{UsersFile: "user_id,username,team_name,is_active\nu1,Alice,backend,yes please\n"}},
+		{name: "reviewer of unknown pr", files: map[string]string{ReviewersFile: "pull_request_id,user_id\npr-1,u1\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadZip(writeFiles(t, tt.files))
+			require.Error(t, err)
+		})
+	}
+
+	_, err := ReadZip([]byte("not a zip"))
+	require.Error(t, err)
+}
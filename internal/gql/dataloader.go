@@ -0,0 +1,119 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type fetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type thunk[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx    context.Context
+	keys   []K
+	thunks map[K]*thunk[V]
+}
+
+// Loader collects keys requested by concurrently running resolvers during a
+// short window and fetches them with a single call. Results are cached for
+// the lifetime of the loader, which is one GraphQL request.
+type Loader[K comparable, V any] struct {
+	fetch    fetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*thunk[V]
+	pending *batch[K, V]
+}
+
+func NewLoader[K comparable, V any](fetch fetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*thunk[V]),
+	}
+}
+
+// Load returns the value for key. A missing key yields the zero value of V.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	return l.await(ctx, l.enqueue(ctx, key))
+}
+
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	thunks := make([]*thunk[V], len(keys))
+	for i, key := range keys {
+		thunks[i] = l.enqueue(ctx, key)
+	}
+	values := make([]V, len(keys))
+	for i, t := range thunks {
+		v, err := l.await(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K) *thunk[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t, ok := l.cache[key]; ok {
+		return t
+	}
+	t := &thunk[V]{done: make(chan struct{})}
+	l.cache[key] = t
+
+	if l.pending == nil {
+		b := &batch[K, V]{ctx: ctx, thunks: make(map[K]*thunk[V])}
+		l.pending = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.thunks[key] = t
+	if len(b.keys) >= l.maxBatch {
+		l.pending = nil
+		go l.run(b)
+	}
+	return t
+}
+
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		// already dispatched because it reached maxBatch
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+	for key, t := range b.thunks {
+		t.value, t.err = values[key], err
+		close(t.done)
+	}
+}
+
+func (l *Loader[K, V]) await(ctx context.Context, t *thunk[V]) (V, error) {
+	select {
+	case <-t.done:
+		return t.value, t.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,235 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func bool_pointer(x bool) *bool {
+	return &x
+}
+
+// fakeService serves a fixed data set and counts batch calls.
+type fakeService struct {
+	mu    sync.Mutex
+	calls map[string]int
+
+	users     map[string]models.User
+	prs       map[string]models.PullRequest
+	reviewers map[string][]string
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		calls: make(map[string]int),
+		users: map[string]models.User{
+			"u1": {UserId: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+			"u2": {UserId: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+			"u3": {UserId: "u3", Username: "Carol", TeamName: "backend", IsActive: true},
+			"u9": {UserId: "u9", Username: "Zed", TeamName: "frontend", IsActive: true},
+		},
+		prs: map[string]models.PullRequest{
+			"pr-1": {PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", Status: models.StatusOpen},
+			"pr-2": {PullRequestId: "pr-2", PullRequestName: "Fix login", AuthorId: "u9", Status: models.StatusOpen},
+			"pr-3": {PullRequestId: "pr-3", PullRequestName: "Old one", AuthorId: "u2", Status: models.StatusMerged},
+		},
+		reviewers: map[string][]string{
+			"pr-1": {"u2", "u3"},
+			"pr-2": {"u1", "u2"},
+			"pr-3": {"u3"},
+		},
+	}
+}
+
+func (f *fakeService) count(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[name]++
+}
+
+func (f *fakeService) GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error) {
+	f.count("GetTeamWithMembers")
+	if teamName != "backend" {
+		return nil, models.ErrTeamNotFound
+	}
+	team := &models.Team{TeamName: teamName}
+	for _, id := range []string{"u1", "u2", "u3"} {
+		u := f.users[id]
+		team.Members = append(team.Members, models.TeamMember{UserId: u.UserId, Username: u.Username, IsActive: bool_pointer(u.IsActive)})
+	}
+	return team, nil
+}
+
+func (f *fakeService) GetUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error) {
+	f.count("GetUsersByIDs")
+	out := make(map[string]models.User)
+	for _, id := range ids {
+		if u, ok := f.users[id]; ok {
+			out[id] = u
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeService) GetMembersOfTeams(ctx context.Context, teamNames []string) (map[string][]models.User, error) {
+	f.count("GetMembersOfTeams")
+	out := make(map[string][]models.User)
+	for _, name := range teamNames {
+		for _, id := range []string{"u1", "u2", "u3", "u9"} {
+			if u := f.users[id]; u.TeamName == name {
+				out[name] = append(out[name], u)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeService) GetPullRequestsByIDs(ctx context.Context, prIDs []string) (map[string]models.PullRequest, error) {
+	f.count("GetPullRequestsByIDs")
+	out := make(map[string]models.PullRequest)
+	for _, id := range prIDs {
+		if pr, ok := f.prs[id]; ok {
+			out[id] = pr
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeService) GetReviewersOfPullRequests(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	f.count("GetReviewersOfPullRequests")
+	out := make(map[string][]string)
+	for _, id := range prIDs {
+		out[id] = f.reviewers[id]
+	}
+	return out, nil
+}
+
+func (f *fakeService) GetReviewsOfUsers(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	f.count("GetReviewsOfUsers")
+	out := make(map[string][]string)
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3"} {
+		for _, reviewer := range f.reviewers[prID] {
+			out[reviewer] = append(out[reviewer], prID)
+		}
+	}
+	return out, nil
+}
+
+func execQuery(t *testing.T, service Service, query string) map[string]any {
+	t.Helper()
+	h, err := NewHandler(service, slog.Default())
+	require.NoError(t, err)
+
+	body, err := json.Marshal(map[string]any{"query": query})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data   map[string]any `json:"data"`
+		Errors []any          `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Empty(t, resp.Errors)
+	return resp.Data
+}
+
+func TestTeamMembersOpenReviewsAuthors_Batched(t *testing.T) {
+	service := newFakeService()
+
+	data := execQuery(t, service, `{
+		team(name: "backend") {
+			name
+			members {
+				id
+				reviews(status: OPEN) {
+					id
+					author { username }
+				}
+			}
+		}
+	}`)
+
+	team := data["team"].(map[string]any)
+	require.Equal(t, "backend", team["name"])
+	members := team["members"].([]any)
+	require.Len(t, members, 3)
+
+	// Alice reviews pr-2 authored by Zed from another team
+	alice := members[0].(map[string]any)
+	reviews := alice["reviews"].([]any)
+	require.Len(t, reviews, 1)
+	require.Equal(t, "Zed", reviews[0].(map[string]any)["author"].(map[string]any)["username"])
+
+	// Carol's merged pr-3 is filtered out
+	carol := members[2].(map[string]any)
+	require.Len(t, carol["reviews"].([]any), 1)
+
+	require.Equal(t, 1, service.calls["GetTeamWithMembers"])
+	require.Equal(t, 1, service.calls["GetReviewsOfUsers"])
+	require.Equal(t, 1, service.calls["GetPullRequestsByIDs"])
+	require.Equal(t, 1, service.calls["GetUsersByIDs"])
+}
+
+func TestPullRequestReviewersAndTeam(t *testing.T) {
+	service := newFakeService()
+
+	data := execQuery(t, service, `{
+		pullRequest(id: "pr-1") {
+			name
+			status
+			reviewers { username team { name } }
+		}
+	}`)
+
+	pr := data["pullRequest"].(map[string]any)
+	require.Equal(t, "Add search", pr["name"])
+	require.Equal(t, "OPEN", pr["status"])
+	reviewers := pr["reviewers"].([]any)
+	require.Len(t, reviewers, 2)
+	require.Equal(t, "Bob", reviewers[0].(map[string]any)["username"])
+	require.Equal(t, "backend", reviewers[1].(map[string]any)["team"].(map[string]any)["name"])
+}
+
+func TestNotFoundIsNull(t *testing.T) {
+	data := execQuery(t, newFakeService(), `{ team(name: "unknown") { name } user(id: "nobody") { id } }`)
+	require.Nil(t, data["team"])
+	require.Nil(t, data["user"])
+}
+
+func TestLoader_CachesAndBatches(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		out := make(map[int]int, len(keys))
+		for _, k := range keys {
+			out[k] = k * 10
+		}
+		return out, nil
+	}, loaderWait, 2)
+
+	values, err := loader.LoadMany(context.Background(), []int{1, 2, 3, 1})
+	require.NoError(t, err)
+	require.Equal(t, []int{10, 20, 30, 10}, values)
+	require.Equal(t, 2, calls)
+
+	v, err := loader.Load(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, 20, v)
+	require.Equal(t, 2, calls)
+}
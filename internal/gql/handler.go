@@ -0,0 +1,88 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sugyk/avito_test_task/internal/logging"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+type Service interface {
+	GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error)
+	GetUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error)
+	GetMembersOfTeams(ctx context.Context, teamNames []string) (map[string][]models.User, error)
+	GetPullRequestsByIDs(ctx context.Context, prIDs []string) (map[string]models.PullRequest, error)
+	GetReviewersOfPullRequests(ctx context.Context, prIDs []string) (map[string][]string, error)
+	GetReviewsOfUsers(ctx context.Context, userIDs []string) (map[string][]string, error)
+}
+
+type Handler struct {
+	schema  *graphql.Schema
+	service Service
+	logger  *slog.Logger
+}
+
+func NewHandler(service Service, logger *slog.Logger) (*Handler, error) {
+	schema, err := graphql.ParseSchema(
+		schemaSDL,
+		&rootResolver{service: service},
+		graphql.MaxParallelism(50),
+		graphql.MaxDepth(10),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse graphql schema: %w", err)
+	}
+	return &Handler{
+		schema:  schema,
+		service: service,
+		logger:  logger,
+	}, nil
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status, err = http.StatusRequestEntityTooLarge, models.ErrBodyTooLarge
+		}
+		h.sendJSON(w, status, models.ErrorResponse{
+			Error: models.Error{Code: models.InvalidInputErrorCode, Message: err.Error()},
+		})
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.service))
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, err := range resp.Errors {
+		logging.FromContext(ctx, h.logger).Error("graphql error", "error", err.Error())
+	}
+	h.sendJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response", "error", err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 500
+)
+
+type loaders struct {
+	users        *Loader[string, models.User]
+	teamMembers  *Loader[string, []models.User]
+	pullRequests *Loader[string, models.PullRequest]
+	reviewers    *Loader[string, []string]
+	reviews      *Loader[string, []string]
+}
+
+func newLoaders(service Service) *loaders {
+	return &loaders{
+		users:        NewLoader(service.GetUsersByIDs, loaderWait, loaderMaxBatch),
+		teamMembers:  NewLoader(service.GetMembersOfTeams, loaderWait, loaderMaxBatch),
+		pullRequests: NewLoader(service.GetPullRequestsByIDs, loaderWait, loaderMaxBatch),
+		reviewers:    NewLoader(service.GetReviewersOfPullRequests, loaderWait, loaderMaxBatch),
+		reviews:      NewLoader(service.GetReviewsOfUsers, loaderWait, loaderMaxBatch),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
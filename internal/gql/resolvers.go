@@ -0,0 +1,174 @@
+package gql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	service Service
+}
+
+func (r *rootResolver) Team(ctx context.Context, args struct{ Name string }) (*teamResolver, error) {
+	team, err := r.service.GetTeamWithMembers(ctx, args.Name)
+	if errors.Is(err, models.ErrTeamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	members := make([]models.User, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, models.User{
+			UserId:   m.UserId,
+			Username: m.Username,
+			TeamName: team.TeamName,
+			IsActive: m.IsActive != nil && *m.IsActive,
+		})
+	}
+	return &teamResolver{name: team.TeamName, members: members}, nil
+}
+
+func (r *rootResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	return loadUser(ctx, string(args.ID))
+}
+
+func (r *rootResolver) PullRequest(ctx context.Context, args struct{ ID graphql.ID }) (*pullRequestResolver, error) {
+	pr, err := loadersFrom(ctx).pullRequests.Load(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if pr.PullRequestId == "" {
+		return nil, nil
+	}
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+type teamResolver struct {
+	name    string
+	members []models.User
+}
+
+func (t *teamResolver) Name() string {
+	return t.name
+}
+
+func (t *teamResolver) Members(ctx context.Context) ([]*userResolver, error) {
+	members := t.members
+	if members == nil {
+		var err error
+		members, err = loadersFrom(ctx).teamMembers.Load(ctx, t.name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resolvers := make([]*userResolver, 0, len(members))
+	for _, m := range members {
+		resolvers = append(resolvers, &userResolver{user: m})
+	}
+	return resolvers, nil
+}
+
+type userResolver struct {
+	user models.User
+}
+
+func loadUser(ctx context.Context, id string) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserId == "" {
+		return nil, nil
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.UserId)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) IsActive() bool {
+	return u.user.IsActive
+}
+
+func (u *userResolver) Team() *teamResolver {
+	if u.user.TeamName == "" {
+		return nil
+	}
+	return &teamResolver{name: u.user.TeamName}
+}
+
+func (u *userResolver) Reviews(ctx context.Context, args struct{ Status *string }) ([]*pullRequestResolver, error) {
+	l := loadersFrom(ctx)
+	prIDs, err := l.reviews.Load(ctx, u.user.UserId)
+	if err != nil {
+		return nil, err
+	}
+	prs, err := l.pullRequests.LoadMany(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*pullRequestResolver, 0, len(prs))
+	for _, pr := range prs {
+		if args.Status != nil && string(pr.Status) != *args.Status {
+			continue
+		}
+		resolvers = append(resolvers, &pullRequestResolver{pr: pr})
+	}
+	return resolvers, nil
+}
+
+type pullRequestResolver struct {
+	pr models.PullRequest
+}
+
+func (p *pullRequestResolver) ID() graphql.ID {
+	return graphql.ID(p.pr.PullRequestId)
+}
+
+func (p *pullRequestResolver) Name() string {
+	return p.pr.PullRequestName
+}
+
+func (p *pullRequestResolver) Status() string {
+	return string(p.pr.Status)
+}
+
+func (p *pullRequestResolver) CreatedAt() *string {
+	return p.pr.CreatedAt
+}
+
+func (p *pullRequestResolver) MergedAt() *string {
+	return p.pr.MergedAt
+}
+
+func (p *pullRequestResolver) Author(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, p.pr.AuthorId)
+}
+
+func (p *pullRequestResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	l := loadersFrom(ctx)
+	ids, err := l.reviewers.Load(ctx, p.pr.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	users, err := l.users.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*userResolver, 0, len(users))
+	for _, user := range users {
+		if user.UserId != "" {
+			resolvers = append(resolvers, &userResolver{user: user})
+		}
+	}
+	return resolvers, nil
+}
@@ -0,0 +1,85 @@
+package grpcapi
+
+import (
+	"github.com/Sugyk/avito_test_task/internal/models"
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+)
+
+func teamFromProto(team *pb.Team) *models.Team {
+	if team == nil {
+		return &models.Team{}
+	}
+	members := make([]models.TeamMember, 0, len(team.GetMembers()))
+	for _, m := range team.GetMembers() {
+		isActive := m.GetIsActive()
+		members = append(members, models.TeamMember{
+			UserId:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: &isActive,
+		})
+	}
+	return &models.Team{
+		TeamName: team.GetTeamName(),
+		Members:  members,
+	}
+}
+
+func teamToProto(team *models.Team) *pb.Team {
+	members := make([]*pb.TeamMember, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, &pb.TeamMember{
+			UserId:   m.UserId,
+			Username: m.Username,
+			IsActive: m.IsActive != nil && *m.IsActive,
+		})
+	}
+	return &pb.Team{
+		TeamName: team.TeamName,
+		Members:  members,
+	}
+}
+
+func userToProto(user *models.User) *pb.User {
+	return &pb.User{
+		UserId:   user.UserId,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+}
+
+func statusToProto(s models.Status) pb.PullRequestStatus {
+	switch s {
+	case models.StatusOpen:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case models.StatusMerged:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func pullRequestToProto(pr *models.PullRequest) *pb.PullRequest {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	return &pb.PullRequest{
+		PullRequestId:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorId,
+		Status:            statusToProto(pr.Status),
+		AssignedReviewers: reviewers,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+func pullRequestShortToProto(pr models.PullRequestShort) *pb.PullRequestShort {
+	return &pb.PullRequestShort{
+		PullRequestId:   pr.PullRequestId,
+		PullRequestName: pr.PullRequestName,
+		AuthorId:        pr.AuthorId,
+		Status:          statusToProto(pr.Status),
+	}
+}
@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "prmanager"
+
+type errorMapping struct {
+	err     error
+	code    codes.Code
+	apiCode string
+}
+
+// errorMappings mirror the HTTP handlers: the same domain error gets the same
+// API error code on both transports, attached to the status as ErrorInfo.
+var errorMappings = []errorMapping{
+	{models.ErrTeamExists, codes.AlreadyExists, models.TeamExistsErrorCode},
+	{models.ErrPRAlreadyExists, codes.AlreadyExists, models.PrExistsErrorCode},
+	{models.ErrTeamNotFound, codes.NotFound, models.NotFoundErrorCode},
+	{models.ErrUserNotFound, codes.NotFound, models.NotFoundErrorCode},
+	{models.ErrAuthorNotFound, codes.NotFound, models.NotFoundErrorCode},
+	{models.ErrPRNotFound, codes.NotFound, models.NotFoundErrorCode},
+	{models.ErrReviewerNotFound, codes.NotFound, models.NotFoundErrorCode},
+	{models.ErrReviewerUnavailable, codes.InvalidArgument, models.InvalidInputErrorCode},
+	{models.ErrNoSlotForCodeOwner, codes.InvalidArgument, models.InvalidInputErrorCode},
+	{models.ErrUnsupportedPRID, codes.InvalidArgument, models.InvalidInputErrorCode},
+	{models.ErrAuthorWithoutTeam, codes.InvalidArgument, models.InvalidInputErrorCode},
+	{models.ErrTooManyRequired, codes.FailedPrecondition, models.ReviewerLimitErrorCode},
+	{models.ErrReassigningMergedPR, codes.FailedPrecondition, models.PrMergedErrorCode},
+	{models.ErrUserNotAssignedToPR, codes.FailedPrecondition, models.NotAssignedErrorCode},
+	{models.ErrNoActiveCandidates, codes.FailedPrecondition, models.NoCandidateErrorCode},
+}
+
+func (s *Server) statusFromError(err error) error {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return newStatus(m.code, m.apiCode, m.err.Error())
+		}
+	}
+	s.logger.Error("internal error", "error", err.Error())
+	return newStatus(codes.Internal, models.InternalErrorCode, models.ErrInternalError.Error())
+}
+
+func invalidArgument(err error) error {
+	return newStatus(codes.InvalidArgument, models.InvalidInputErrorCode, err.Error())
+}
+
+func newStatus(code codes.Code, apiCode, message string) error {
+	st := status.New(code, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: apiCode,
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+)
+
+func (s *Server) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.CreatePullRequestResponse, error) {
+	request := models.PullRequestCreateRequest{
+		PullRequestId:     req.GetPullRequestId(),
+		PullRequestName:   req.GetPullRequestName(),
+		AuthorId:          req.GetAuthorId(),
+		Files:             req.GetFiles(),
+		Labels:            req.GetLabels(),
+		RequiredReviewers: req.GetRequiredReviewers(),
+		ExcludedReviewers: req.GetExcludedReviewers(),
+	}
+	if err := request.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	pr, err := s.service.PullRequestCreate(ctx, request.ToPullRequest())
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.CreatePullRequestResponse{Pr: pullRequestToProto(pr)}, nil
+}
+
+func (s *Server) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.MergePullRequestResponse, error) {
+	request := models.PullRequestMergeRequest{
+		PullRequestId: req.GetPullRequestId(),
+	}
+	if err := request.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	pr, err := s.service.PullRequestMerge(ctx, request.ToPullRequest())
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.MergePullRequestResponse{Pr: pullRequestToProto(pr)}, nil
+}
+
+func (s *Server) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	request := models.PullRequestReassignRequest{
+		PullRequestId: req.GetPullRequestId(),
+		OldReviewerId: req.GetOldReviewerId(),
+	}
+	if err := request.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	pr, replacedBy, err := s.service.PullRequestReassign(ctx, request.PullRequestId, request.OldReviewerId)
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.ReassignReviewerResponse{
+		Pr:         pullRequestToProto(pr),
+		ReplacedBy: replacedBy,
+	}, nil
+}
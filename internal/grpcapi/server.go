@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	pb.UnimplementedTeamServiceServer
+	pb.UnimplementedUserServiceServer
+	pb.UnimplementedPullRequestServiceServer
+
+	service handlers.Service
+	logger  *slog.Logger
+
+	addr   string
+	server *grpc.Server
+}
+
+func NewServer(port string, service handlers.Service, logger *slog.Logger) *Server {
+	s := &Server{
+		service: service,
+		logger:  logger,
+		addr:    ":" + port,
+		server:  grpc.NewServer(),
+	}
+	pb.RegisterTeamServiceServer(s.server, s)
+	pb.RegisterUserServiceServer(s.server, s)
+	pb.RegisterPullRequestServiceServer(s.server, s)
+	reflection.Register(s.server)
+	return s
+}
+
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Shutdown waits for in-flight calls to finish, falling back to a hard stop
+// when ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
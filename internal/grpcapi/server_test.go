@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestConn(t *testing.T) (*handlers.MockService, *grpc.ClientConn) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := handlers.NewMockService(ctrl)
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer("0", mockService, slog.Default())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return mockService, conn
+}
+
+func apiCode(t *testing.T, err error) string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	mockService, conn := newTestConn(t)
+	client := pb.NewPullRequestServiceClient(conn)
+
+	expected := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+	}
+	mockService.EXPECT().
+		PullRequestCreate(gomock.Any(), &models.PullRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1"}).
+		Return(expected, nil)
+
+	resp, err := client.CreatePullRequest(context.Background(), &pb.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pr-1", resp.GetPr().GetPullRequestId())
+	require.Equal(t, pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, resp.GetPr().GetStatus())
+	require.Equal(t, []string{"u2", "u3"}, resp.GetPr().GetAssignedReviewers())
+}
+
+func TestCreatePullRequest_ReviewerFields(t *testing.T) {
+	mockService, conn := newTestConn(t)
+	client := pb.NewPullRequestServiceClient(conn)
+
+	mockService.EXPECT().
+		PullRequestCreate(gomock.Any(), &models.PullRequest{
+			PullRequestId:     "pr-1",
+			PullRequestName:   "Add search",
+			AuthorId:          "u1",
+			Files:             []string{"search/index.go"},
+			Labels:            []string{"backend"},
+			RequiredReviewers: []string{"u2"},
+			ExcludedReviewers: []string{"u3"},
+		}).
+		Return(&models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u2", "u4"}}, nil)
+
+	resp, err := client.CreatePullRequest(context.Background(), &pb.CreatePullRequestRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		Files:             []string{"search/index.go"},
+		Labels:            []string{"backend"},
+		RequiredReviewers: []string{"u2"},
+		ExcludedReviewers: []string{"u3"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2", "u4"}, resp.GetPr().GetAssignedReviewers())
+
+	// a reviewer can not be both required and excluded
+	_, err = client.CreatePullRequest(context.Background(), &pb.CreatePullRequestRequest{
+		PullRequestId:     "pr-2",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		RequiredReviewers: []string{"u2"},
+		ExcludedReviewers: []string{"u2"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.EXPECT().PullRequestCreate(gomock.Any(), gomock.Any()).Return(nil, models.ErrReviewerNotFound)
+	_, err = client.CreatePullRequest(context.Background(), &pb.CreatePullRequestRequest{
+		PullRequestId:     "pr-3",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		RequiredReviewers: []string{"ghost"},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, models.NotFoundErrorCode, apiCode(t, err))
+}
+
+func TestAddTeam_InvalidArgument(t *testing.T) {
+	_, conn := newTestConn(t)
+	client := pb.NewTeamServiceClient(conn)
+
+	_, err := client.AddTeam(context.Background(), &pb.AddTeamRequest{Team: &pb.Team{TeamName: "backend"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, models.InvalidInputErrorCode, apiCode(t, err))
+}
+
+func TestGetReview(t *testing.T) {
+	mockService, conn := newTestConn(t)
+	client := pb.NewUserServiceClient(conn)
+
+	mockService.EXPECT().UsersGetReview(gomock.Any(), "u2").Return([]models.PullRequestShort{
+		{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", Status: models.StatusMerged},
+	}, nil)
+
+	resp, err := client.GetReview(context.Background(), &pb.GetReviewRequest{UserId: "u2"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetPullRequests(), 1)
+	require.Equal(t, pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED, resp.GetPullRequests()[0].GetStatus())
+}
+
+func TestReassignReviewer_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		apiCode string
+	}{
+		{"pr not found", models.ErrPRNotFound, codes.NotFound, models.NotFoundErrorCode},
+		{"user not found", models.ErrUserNotFound, codes.NotFound, models.NotFoundErrorCode},
+		{"merged", models.ErrReassigningMergedPR, codes.FailedPrecondition, models.PrMergedErrorCode},
+		{"not assigned", models.ErrUserNotAssignedToPR, codes.FailedPrecondition, models.NotAssignedErrorCode},
+		{"no candidate", models.ErrNoActiveCandidates, codes.FailedPrecondition, models.NoCandidateErrorCode},
+		{"internal", errors.New("db is down"), codes.Internal, models.InternalErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService, conn := newTestConn(t)
+			client := pb.NewPullRequestServiceClient(conn)
+
+			mockService.EXPECT().PullRequestReassign(gomock.Any(), "pr-1", "u2").Return(nil, "", tt.err)
+
+			_, err := client.ReassignReviewer(context.Background(), &pb.ReassignReviewerRequest{
+				PullRequestId: "pr-1",
+				OldReviewerId: "u2",
+			})
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.apiCode, apiCode(t, err))
+		})
+	}
+}
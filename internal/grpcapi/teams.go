@@ -0,0 +1,31 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+)
+
+func (s *Server) AddTeam(ctx context.Context, req *pb.AddTeamRequest) (*pb.AddTeamResponse, error) {
+	team := teamFromProto(req.GetTeam())
+	if err := team.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	created, err := s.service.CreateOrUpdateTeam(ctx, team)
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.AddTeamResponse{Team: teamToProto(created)}, nil
+}
+
+func (s *Server) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument(errors.New("missing team_name"))
+	}
+	team, err := s.service.GetTeamWithMembers(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.GetTeamResponse{Team: teamToProto(team)}, nil
+}
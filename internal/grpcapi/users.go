@@ -0,0 +1,43 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	pb "github.com/Sugyk/avito_test_task/pkg/pb/prmanager/v1"
+)
+
+func (s *Server) SetIsActive(ctx context.Context, req *pb.SetIsActiveRequest) (*pb.SetIsActiveResponse, error) {
+	isActive := req.GetIsActive()
+	request := models.UsersSetIsActiveRequest{
+		UserId:   req.GetUserId(),
+		IsActive: &isActive,
+	}
+	if err := request.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	user, err := s.service.UsersSetIsActive(ctx, request.UserId, isActive)
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	return &pb.SetIsActiveResponse{User: userToProto(user)}, nil
+}
+
+func (s *Server) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument(errors.New("missing user_id"))
+	}
+	prs, err := s.service.UsersGetReview(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.statusFromError(err)
+	}
+	resp := &pb.GetReviewResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: make([]*pb.PullRequestShort, 0, len(prs)),
+	}
+	for _, pr := range prs {
+		resp.PullRequests = append(resp.PullRequests, pullRequestShortToProto(pr))
+	}
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker reports liveness of the process and readiness to serve traffic.
+// The service is ready once started, while every dependency check passes and
+// until shutdown begins.
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// AddCheck registers a dependency check run on every readiness probe.
+func (c *Checker) AddCheck(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+func (c *Checker) SetStarted() {
+	c.started.Store(true)
+}
+
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	sendJSON(w, http.StatusOK, Response{Status: statusOK})
+}
+
+func (c *Checker) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	resp := c.Ready(r.Context())
+	status := http.StatusOK
+	if resp.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+	sendJSON(w, status, resp)
+}
+
+func (c *Checker) Ready(ctx context.Context) Response {
+	if c.shuttingDown.Load() {
+		return Response{Status: statusUnavailable, Checks: map[string]string{"shutdown": "in progress"}}
+	}
+	if !c.started.Load() {
+		return Response{Status: statusUnavailable, Checks: map[string]string{"startup": "in progress"}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp := Response{Status: statusOK, Checks: make(map[string]string, len(c.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, ch := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := statusOK
+			if err := ch.fn(ctx); err != nil {
+				result = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[ch.name] = result
+			if result != statusOK {
+				resp.Status = statusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+	return resp
+}
+
+func sendJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, handler http.HandlerFunc) (int, Response) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	var resp Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return w.Code, resp
+}
+
+func TestReady_Lifecycle(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.AddCheck("database", func(ctx context.Context) error { return nil })
+
+	code, _ := probe(t, c.ReadyHandler)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	c.SetStarted()
+	code, resp := probe(t, c.ReadyHandler)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ok", resp.Checks["database"])
+
+	c.SetShuttingDown()
+	code, _ = probe(t, c.ReadyHandler)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	code, _ = probe(t, c.LiveHandler)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestReady_FailingCheck(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.AddCheck("database", func(ctx context.Context) error { return nil })
+	c.AddCheck("migrations", func(ctx context.Context) error { return errors.New("dirty migration version 3") })
+	c.SetStarted()
+
+	code, resp := probe(t, c.ReadyHandler)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "ok", resp.Checks["database"])
+	require.Equal(t, "dirty migration version 3", resp.Checks["migrations"])
+}
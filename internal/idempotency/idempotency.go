@@ -0,0 +1,174 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/logging"
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+const (
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// claimLease is how long a request holds its key. A key still in progress
+	// after that, e.g. because the replica crashed, can be claimed again.
+	claimLease = time.Minute
+)
+
+type Store interface {
+	ClaimIdempotencyKey(ctx context.Context, client, key, route, requestHash string, staleBefore time.Time) (*models.IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, client, key, route string, statusCode int, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, client, key, route string) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Middleware makes requests to routes carrying an Idempotency-Key header
+// safe to retry: the first response for a key is stored and replayed for
+// later requests with the same key and body. Reusing a key with another body,
+// or while the first request is still running, is answered with 409.
+// Server errors are not stored, so the request can be retried for real.
+// Keys are scoped by the client clientKey identifies the request with, so
+// clients can not see each other's responses. Routes are mux patterns, e.g.
+// "POST /pullRequest/create".
+func Middleware(store Store, mux *http.ServeMux, routes []string, clientKey func(*http.Request) string, logger *slog.Logger) func(http.Handler) http.Handler {
+	enabled := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		enabled[route] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(KeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			_, route := mux.Handler(r)
+			if !enabled[route] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			log := logging.FromContext(r.Context(), logger)
+
+			if len(key) > maxKeyLength {
+				writeError(w, http.StatusBadRequest, models.InvalidInputErrorCode, errors.New("idempotency key is too long"))
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writeError(w, http.StatusRequestEntityTooLarge, models.InvalidInputErrorCode, models.ErrBodyTooLarge)
+					return
+				}
+				writeError(w, http.StatusBadRequest, models.InvalidInputErrorCode, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			sum := sha256.Sum256(body)
+			hash := hex.EncodeToString(sum[:])
+
+			client := clientKey(r)
+			record, claimed, err := store.ClaimIdempotencyKey(r.Context(), client, key, route, hash, time.Now().Add(-claimLease))
+			if err != nil {
+				log.Error("idempotency: can not claim key", "error", err.Error())
+				writeError(w, http.StatusInternalServerError, models.InternalErrorCode, models.ErrInternalError)
+				return
+			}
+			if !claimed {
+				replay(w, record, hash)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// the client may be gone, which is exactly when it will retry
+			ctx := context.WithoutCancel(r.Context())
+			if rec.status >= http.StatusInternalServerError {
+				if err := store.ReleaseIdempotencyKey(ctx, client, key, route); err != nil {
+					log.Error("idempotency: can not release key", "error", err.Error())
+				}
+				return
+			}
+			if err := store.CompleteIdempotencyKey(ctx, client, key, route, rec.status, rec.body.Bytes()); err != nil {
+				log.Error("idempotency: can not store response", "error", err.Error())
+			}
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, record *models.IdempotencyRecord, hash string) {
+	if record.RequestHash != hash {
+		writeError(w, http.StatusConflict, models.IdempotencyConflictErrorCode, models.ErrIdempotencyKeyReused)
+		return
+	}
+	if record.StatusCode == nil {
+		writeError(w, http.StatusConflict, models.IdempotencyConflictErrorCode, models.ErrIdempotencyInProgress)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(*record.StatusCode)
+	_, _ = w.Write(record.ResponseBody)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error: models.Error{
+			Code:    code,
+			Message: err.Error(),
+		},
+	})
+}
+
+// RunCleanup deletes keys older than ttl every interval until ctx is done.
+func RunCleanup(ctx context.Context, store Store, ttl, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		deleted, err := store.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-ttl))
+		if err != nil {
+			logger.Error("idempotency: cleanup failed", "error", err.Error())
+			continue
+		}
+		if deleted > 0 {
+			logger.Info("idempotency: expired keys deleted", "count", deleted)
+		}
+	}
+}
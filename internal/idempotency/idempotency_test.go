@@ -0,0 +1,252 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*models.IdempotencyRecord
+	claimedAt map[string]time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]*models.IdempotencyRecord), claimedAt: make(map[string]time.Time)}
+}
+
+func (s *memoryStore) ClaimIdempotencyKey(_ context.Context, client, key, route, hash string, staleBefore time.Time) (*models.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := client + route + key
+	if record, ok := s.records[id]; ok && (record.StatusCode != nil || !s.claimedAt[id].Before(staleBefore)) {
+		copied := *record
+		return &copied, false, nil
+	}
+	record := &models.IdempotencyRecord{Client: client, Key: key, Route: route, RequestHash: hash}
+	s.records[id] = record
+	s.claimedAt[id] = time.Now()
+	return record, true, nil
+}
+
+func (s *memoryStore) CompleteIdempotencyKey(_ context.Context, client, key, route string, status int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[client+route+key]
+	record.StatusCode = &status
+	record.ResponseBody = body
+	return nil
+}
+
+func (s *memoryStore) ReleaseIdempotencyKey(_ context.Context, client, key, route string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, client+route+key)
+	return nil
+}
+
+func (s *memoryStore) DeleteIdempotencyKeysBefore(context.Context, time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := int64(len(s.records))
+	s.records = make(map[string]*models.IdempotencyRecord)
+	return n, nil
+}
+
+const route = "POST /pullRequest/reassign"
+
+// newHandler counts how often the reassign handler really runs.
+func newHandler(store Store, status *int) (http.Handler, *int) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(*status)
+		_, _ = w.Write([]byte(`{"replaced_by": "u` + string(rune('0'+calls)) + `"}`))
+	})
+	mux.HandleFunc("POST /pullRequest/merge", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	return Middleware(store, mux, []string{route}, clientKey, slog.Default())(mux), &calls
+}
+
+// clientKey tells clients apart by the peer address, like the rate limiter
+// does for requests without a token.
+func clientKey(r *http.Request) string {
+	return r.RemoteAddr
+}
+
+func send(h http.Handler, target, key, body string) *httptest.ResponseRecorder {
+	return sendFrom(h, "192.0.2.1:1234", target, key, body)
+}
+
+func sendFrom(h http.Handler, addr, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.RemoteAddr = addr
+	if key != "" {
+		req.Header.Set(KeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp models.ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp.Error.Code
+}
+
+func TestMiddleware_ReplaysResponse(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(newMemoryStore(), &status)
+	body := `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`
+
+	first := send(h, "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Empty(t, first.Header().Get(ReplayedHeader))
+
+	replayed := send(h, "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusOK, replayed.Code)
+	require.Equal(t, "true", replayed.Header().Get(ReplayedHeader))
+	require.Equal(t, first.Body.String(), replayed.Body.String())
+	require.Equal(t, 1, *calls)
+
+	// another key is another request
+	send(h, "/pullRequest/reassign", "key-2", body)
+	require.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_KeyReusedWithOtherBody(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(newMemoryStore(), &status)
+
+	send(h, "/pullRequest/reassign", "key-1", `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`)
+	rec := send(h, "/pullRequest/reassign", "key-1", `{"pull_request_id": "pr-2", "old_reviewer_id": "u2"}`)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Equal(t, models.IdempotencyConflictErrorCode, errorCode(t, rec))
+	require.Equal(t, 1, *calls)
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	store := newMemoryStore()
+	status := http.StatusOK
+	h, calls := newHandler(store, &status)
+	body := `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`
+
+	_, claimed, err := store.ClaimIdempotencyKey(context.Background(), "192.0.2.1:1234", "key-1", route, "", time.Now().Add(-claimLease))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	rec := send(h, "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Equal(t, 0, *calls)
+
+	// a claim older than the lease is taken over
+	store.mu.Lock()
+	store.claimedAt["192.0.2.1:1234"+route+"key-1"] = time.Now().Add(-2 * claimLease)
+	store.mu.Unlock()
+	rec = send(h, "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 1, *calls)
+}
+
+func TestMiddleware_KeysAreScopedByClient(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(newMemoryStore(), &status)
+	body := `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`
+
+	first := sendFrom(h, "192.0.2.1:1234", "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusOK, first.Code)
+	// another client with the same key runs its own request
+	other := sendFrom(h, "192.0.2.2:1234", "/pullRequest/reassign", "key-1", body)
+	require.Equal(t, http.StatusOK, other.Code)
+	require.Empty(t, other.Header().Get(ReplayedHeader))
+	require.NotEqual(t, first.Body.String(), other.Body.String())
+	require.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_ServerErrorsAreNotStored(t *testing.T) {
+	status := http.StatusInternalServerError
+	h, calls := newHandler(newMemoryStore(), &status)
+	body := `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`
+
+	require.Equal(t, http.StatusInternalServerError, send(h, "/pullRequest/reassign", "key-1", body).Code)
+	status = http.StatusOK
+	require.Equal(t, http.StatusOK, send(h, "/pullRequest/reassign", "key-1", body).Code)
+	require.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_PassThrough(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(newMemoryStore(), &status)
+	body := `{"pull_request_id": "pr-1", "old_reviewer_id": "u2"}`
+
+	// without a key every request runs
+	send(h, "/pullRequest/reassign", "", body)
+	send(h, "/pullRequest/reassign", "", body)
+	// routes without idempotency ignore the key
+	send(h, "/pullRequest/merge", "key-1", body)
+	send(h, "/pullRequest/merge", "key-1", body)
+	require.Equal(t, 4, *calls)
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(newMemoryStore(), &status)
+
+	rec := send(h, "/pullRequest/reassign", strings.Repeat("k", maxKeyLength+1), `{}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, 0, *calls)
+}
+
+type failingStore struct{ *memoryStore }
+
+func (*failingStore) ClaimIdempotencyKey(context.Context, string, string, string, string, time.Time) (*models.IdempotencyRecord, bool, error) {
+	return nil, false, errors.New("db down")
+}
+
+func TestMiddleware_StoreError(t *testing.T) {
+	status := http.StatusOK
+	h, calls := newHandler(&failingStore{newMemoryStore()}, &status)
+
+	rec := send(h, "/pullRequest/reassign", "key-1", `{}`)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, models.InternalErrorCode, errorCode(t, rec))
+	require.Equal(t, 0, *calls)
+}
+
+func TestRunCleanup(t *testing.T) {
+	store := newMemoryStore()
+	_, _, _ = store.ClaimIdempotencyKey(context.Background(), "192.0.2.1:1234", "key-1", route, "", time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunCleanup(ctx, store, time.Hour, time.Millisecond, slog.Default())
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.records) == 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
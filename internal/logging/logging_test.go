@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestMiddleware_CorrelatesLogLines(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	h := Middleware(base)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context(), nil).Info("inside handler")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, "abc-123", rec.Header().Get(RequestIDHeader))
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	require.Equal(t, "inside handler", lines[0]["msg"])
+	require.Equal(t, "abc-123", lines[0]["request_id"])
+	require.Equal(t, "request completed", lines[1]["msg"])
+	require.Equal(t, "abc-123", lines[1]["request_id"])
+	require.Equal(t, "GET", lines[1]["method"])
+	require.Equal(t, "/team/get", lines[1]["path"])
+	require.EqualValues(t, http.StatusNotFound, lines[1]["status"])
+}
+
+func TestMiddleware_GeneratesRequestID(t *testing.T) {
+	base := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	var seen string
+	h := Middleware(base)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	for _, incoming := range []string{"", "bad id with spaces", strings.Repeat("x", maxRequestIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if incoming != "" {
+			req.Header.Set(RequestIDHeader, incoming)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		require.Len(t, seen, 32)
+		require.Equal(t, seen, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestFromContext_Fallback(t *testing.T) {
+	fallback := slog.Default()
+	require.Same(t, fallback, FromContext(context.Background(), fallback))
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	require.Same(t, logger, FromContext(WithLogger(context.Background(), logger), fallback))
+}
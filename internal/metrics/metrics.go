@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pr_manager"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration *prometheus.HistogramVec
+
+	prsCreated        prometheus.Counter
+	reviewersAssigned prometheus.Counter
+	reassignments     prometheus.Counter
+	noCandidate       *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		prsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Number of created pull requests.",
+		}),
+		reviewersAssigned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewers_assigned_total",
+			Help:      "Number of reviewers assigned to pull requests, including reassignments.",
+		}),
+		reassignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Number of successful reviewer reassignments.",
+		}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_failures_total",
+			Help:      "Number of operations that found fewer reviewer candidates than needed.",
+		}, []string{"operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.prsCreated,
+		m.reviewersAssigned,
+		m.reassignments,
+		m.noCandidate,
+	)
+	return m
+}
+
+// RegisterDB exposes connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) PullRequestCreated(reviewers int) {
+	m.prsCreated.Inc()
+	m.reviewersAssigned.Add(float64(reviewers))
+}
+
+func (m *Metrics) ReviewerReassigned() {
+	m.reassignments.Inc()
+	m.reviewersAssigned.Inc()
+}
+
+func (m *Metrics) NoCandidate(operation string) {
+	m.noCandidate.WithLabelValues(operation).Inc()
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware observes request durations labeled with the mux pattern the
+// request matches, so that path values do not blow up the label cardinality.
+func (m *Metrics) Middleware(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			route := "unmatched"
+			if _, pattern := mux.Handler(r); pattern != "" {
+				_, route, _ = strings.Cut(pattern, " ")
+			}
+			m.httpDuration.
+				WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).
+				Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_LabelsByRoute(t *testing.T) {
+	m := New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /team/get", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := m.Middleware(mux)(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/team/get?team_name=a", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/team/get?team_name=b", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	require.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+	require.Equal(t, uint64(2), histogramCount(t, m, "GET", "/team/get", "404"))
+	require.Equal(t, uint64(1), histogramCount(t, m, "GET", "unmatched", "404"))
+}
+
+func TestDomainCounters(t *testing.T) {
+	m := New()
+
+	m.PullRequestCreated(2)
+	m.PullRequestCreated(1)
+	m.ReviewerReassigned()
+	m.NoCandidate("reassign")
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.prsCreated))
+	require.Equal(t, 4.0, testutil.ToFloat64(m.reviewersAssigned))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.reassignments))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.noCandidate.WithLabelValues("reassign")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.PullRequestCreated(2)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(body), "pr_manager_pull_requests_created_total 1"))
+}
+
+func histogramCount(t *testing.T, m *Metrics, method, route, status string) uint64 {
+	t.Helper()
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "pr_manager_http_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["method"] == method && labels["route"] == route && labels["status"] == status {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
@@ -1,7 +1,9 @@
-DROP TABLE IF EXISTS Teams;
+DROP TABLE IF EXISTS PullRequestsUsers;
+
+DROP TABLE IF EXISTS PullRequests;
 
 DROP TABLE IF EXISTS Users;
 
-DROP TABLE IF EXISTS PullRequests;
+DROP TABLE IF EXISTS Teams;
 
-DROP TABLE IF EXISTS PullRequestsUsers;
+DROP TYPE IF EXISTS pr_status;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS IdempotencyKeys(
+    key VARCHAR(255) NOT NULL,
+    route VARCHAR NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER DEFAULT NULL,
+    response_body BYTEA DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON IdempotencyKeys(created_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS UserUnavailability(
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR NOT NULL REFERENCES Users(id),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS user_unavailability_user_id_ends_at_idx ON UserUnavailability(user_id, ends_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ReviewerReassignments;
+
+ALTER TABLE PullRequestsUsers DROP COLUMN IF EXISTS assigned_at;
+
+ALTER TABLE Teams DROP COLUMN IF EXISTS stale_review_hours;
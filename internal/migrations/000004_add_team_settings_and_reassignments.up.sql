@@ -0,0 +1,14 @@
+ALTER TABLE Teams ADD COLUMN IF NOT EXISTS stale_review_hours INTEGER DEFAULT NULL CHECK (stale_review_hours > 0);
+
+ALTER TABLE PullRequestsUsers ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS ReviewerReassignments(
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR NOT NULL REFERENCES PullRequests(id),
+    old_reviewer_id VARCHAR NOT NULL REFERENCES Users(id),
+    new_reviewer_id VARCHAR NOT NULL REFERENCES Users(id),
+    reason VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS reviewer_reassignments_pr_id_idx ON ReviewerReassignments(pr_id);
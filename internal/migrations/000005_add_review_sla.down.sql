@@ -0,0 +1,3 @@
+ALTER TABLE Teams DROP COLUMN IF EXISTS merge_sla_hours;
+
+ALTER TABLE Teams DROP COLUMN IF EXISTS first_review_sla_hours;
@@ -0,0 +1,3 @@
+ALTER TABLE Teams ADD COLUMN IF NOT EXISTS first_review_sla_hours INTEGER DEFAULT NULL CHECK (first_review_sla_hours > 0);
+
+ALTER TABLE Teams ADD COLUMN IF NOT EXISTS merge_sla_hours INTEGER DEFAULT NULL CHECK (merge_sla_hours > 0);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS TeamPathRules;
+
+DROP TABLE IF EXISTS UserExpertise;
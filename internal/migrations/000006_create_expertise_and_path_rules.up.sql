@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS UserExpertise(
+    user_id VARCHAR NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    tag VARCHAR NOT NULL,
+    PRIMARY KEY (user_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS user_expertise_tag_idx ON UserExpertise(tag);
+
+-- a rule with several owners is stored as one row per owner, a rule without
+-- owners as a single row with neither user_id nor tag
+CREATE TABLE IF NOT EXISTS TeamPathRules(
+    id SERIAL PRIMARY KEY,
+    team_name VARCHAR NOT NULL REFERENCES Teams(name) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    pattern VARCHAR NOT NULL,
+    user_id VARCHAR DEFAULT NULL,
+    tag VARCHAR DEFAULT NULL,
+    CHECK (user_id IS NULL OR tag IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS team_path_rules_team_name_idx ON TeamPathRules(team_name, position);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS TeamCodeowners(
+    team_name VARCHAR PRIMARY KEY REFERENCES Teams(name) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- users that must not review a pull request, not necessarily known users
+CREATE TABLE IF NOT EXISTS PullRequestExclusions(
+    pr_id VARCHAR NOT NULL REFERENCES PullRequests(id) ON DELETE CASCADE,
+    user_id VARCHAR NOT NULL,
+    PRIMARY KEY (pr_id, user_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE Teams DROP COLUMN IF EXISTS max_reviewers;
+
+ALTER TABLE Teams DROP COLUMN IF EXISTS min_reviewers;
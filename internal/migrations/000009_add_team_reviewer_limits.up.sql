@@ -0,0 +1,3 @@
+ALTER TABLE Teams ADD COLUMN IF NOT EXISTS min_reviewers INTEGER DEFAULT NULL CHECK (min_reviewers >= 0);
+
+ALTER TABLE Teams ADD COLUMN IF NOT EXISTS max_reviewers INTEGER DEFAULT NULL CHECK (max_reviewers > 0);
@@ -0,0 +1,10 @@
+-- the same key may be held by several clients, keep one record of each
+DELETE FROM IdempotencyKeys a
+USING IdempotencyKeys b
+WHERE a.key = b.key AND a.route = b.route AND a.client > b.client;
+
+ALTER TABLE IdempotencyKeys DROP CONSTRAINT IF EXISTS idempotencykeys_pkey;
+ALTER TABLE IdempotencyKeys ADD PRIMARY KEY (key, route);
+
+ALTER TABLE IdempotencyKeys DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE IdempotencyKeys DROP COLUMN IF EXISTS client;
@@ -0,0 +1,5 @@
+ALTER TABLE IdempotencyKeys ADD COLUMN IF NOT EXISTS client VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE IdempotencyKeys ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE IdempotencyKeys DROP CONSTRAINT IF EXISTS idempotencykeys_pkey;
+ALTER TABLE IdempotencyKeys ADD PRIMARY KEY (client, key, route);
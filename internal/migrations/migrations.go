@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations of the service, so the binary
+// does not depend on files next to it.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,91 @@
+package models
+
+import "fmt"
+
+// Dump is the whole state of the service as exported and imported by the
+// admin endpoints. Users are the members of Teams, reviewer assignments are
+// the AssignedReviewers of PullRequests.
+type Dump struct {
+	Teams        []Team        `json:"teams"`
+	PullRequests []PullRequest `json:"pull_requests"`
+}
+
+// Validate checks the dump on its own. Unlike Team.Validate it accepts teams
+// without members, which the export produces after users moved to other
+// teams.
+func (d *Dump) Validate() error {
+	teams := make(map[string]bool, len(d.Teams))
+	users := make(map[string]bool)
+	for i, team := range d.Teams {
+		if team.TeamName == "" {
+			return fmt.Errorf("team %d: team_name is required", i)
+		}
+		if teams[team.TeamName] {
+			return fmt.Errorf("team %q is listed twice", team.TeamName)
+		}
+		teams[team.TeamName] = true
+		for j, member := range team.Members {
+			if err := member.Validate(); err != nil {
+				return fmt.Errorf("team %q: invalid team member %d: %w", team.TeamName, j, err)
+			}
+			if users[member.UserId] {
+				return fmt.Errorf("user %q is listed twice", member.UserId)
+			}
+			users[member.UserId] = true
+		}
+	}
+
+	prs := make(map[string]bool, len(d.PullRequests))
+	for i, pr := range d.PullRequests {
+		if pr.PullRequestId == "" {
+			return fmt.Errorf("pull request %d: pull_request_id is required", i)
+		}
+		if prs[pr.PullRequestId] {
+			return fmt.Errorf("pull request %q is listed twice", pr.PullRequestId)
+		}
+		prs[pr.PullRequestId] = true
+		if pr.PullRequestName == "" {
+			return fmt.Errorf("pull request %q: pull_request_name is required", pr.PullRequestId)
+		}
+		if pr.AuthorId == "" {
+			return fmt.Errorf("pull request %q: author_id is required", pr.PullRequestId)
+		}
+		if err := pr.Status.Validate(); err != nil {
+			return fmt.Errorf("pull request %q: %w", pr.PullRequestId, err)
+		}
+		reviewers := make(map[string]bool, len(pr.AssignedReviewers))
+		for _, reviewer := range pr.AssignedReviewers {
+			if reviewers[reviewer] {
+				return fmt.Errorf("pull request %q: reviewer %q is assigned twice", pr.PullRequestId, reviewer)
+			}
+			reviewers[reviewer] = true
+		}
+	}
+	return nil
+}
+
+// EntityDiff lists the ids an import creates or changes.
+type EntityDiff struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// ImportReport compares an imported dump with the state before the import.
+// Entities missing from the dump are kept, so nothing is ever deleted.
+type ImportReport struct {
+	DryRun       bool       `json:"dry_run"`
+	Teams        EntityDiff `json:"teams"`
+	Users        EntityDiff `json:"users"`
+	PullRequests EntityDiff `json:"pull_requests"`
+}
+
+// Changed tells if applying the dump modifies anything.
+func (r *ImportReport) Changed() bool {
+	for _, diff := range []EntityDiff{r.Teams, r.Users, r.PullRequests} {
+		if len(diff.Created) > 0 || len(diff.Updated) > 0 {
+			return true
+		}
+	}
+	return false
+}
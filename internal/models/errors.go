@@ -1,37 +1,73 @@
 package models
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/Sugyk/avito_test_task/pkg/apierrors"
+	"github.com/Sugyk/avito_test_task/pkg/apitypes"
+)
 
 var (
-	TeamExistsErrorCode   = "TEAM_EXISTS"
-	PrExistsErrorCode     = "PR_EXISTS"
-	PrMergedErrorCode     = "PR_MERGED"
-	NotAssignedErrorCode  = "NOT_ASSIGNED"
-	NoCandidateErrorCode  = "NO_CANDIDATE"
-	NotFoundErrorCode     = "NOT_FOUND"
-	InvalidInputErrorCode = "INVALID_INPUT"
-	InternalErrorCode     = "INTERNAL_ERROR"
+	TeamExistsErrorCode   = apierrors.CodeTeamExists
+	PrExistsErrorCode     = apierrors.CodePrExists
+	PrMergedErrorCode     = apierrors.CodePrMerged
+	NotAssignedErrorCode  = apierrors.CodeNotAssigned
+	NoCandidateErrorCode  = apierrors.CodeNoCandidate
+	NotFoundErrorCode     = apierrors.CodeNotFound
+	InvalidInputErrorCode = apierrors.CodeInvalidInput
+	InternalErrorCode     = apierrors.CodeInternal
+	RateLimitedErrorCode  = apierrors.CodeRateLimited
+	UnauthorizedErrorCode = apierrors.CodeUnauthorized
+
+	AlreadyAssignedErrorCode = apierrors.CodeAlreadyAssigned
+	ReviewerLimitErrorCode   = apierrors.CodeReviewerLimit
+
+	IdempotencyConflictErrorCode = apierrors.CodeIdempotencyConflict
 )
 
 var (
-	ErrTeamExists          = errors.New("team_name already exists")
-	ErrInternalError       = errors.New("internal server error")
-	ErrTeamNotFound        = errors.New("team_name not found")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrPRAlreadyExists     = errors.New("PR id already exists")
-	ErrAuthorNotFound      = errors.New("author not found")
-	ErrPRNotFound          = errors.New("PR not found")
-	ErrReassigningMergedPR = errors.New("cannot reassign on merged PR")
-	ErrUserNotAssignedToPR = errors.New("reviewer is not assigned to this PR")
-	ErrNoActiveCandidates  = errors.New("no active replacement candidate in team")
-	ErrNoReviewers         = errors.New("no reviewers assigned to PR")
+	ErrTeamExists          = apierrors.ErrTeamExists
+	ErrInternalError       = apierrors.ErrInternalError
+	ErrTeamNotFound        = apierrors.ErrTeamNotFound
+	ErrUserNotFound        = apierrors.ErrUserNotFound
+	ErrPRAlreadyExists     = apierrors.ErrPRAlreadyExists
+	ErrUnsupportedPRID     = apierrors.ErrUnsupportedPRID
+	ErrAuthorNotFound      = apierrors.ErrAuthorNotFound
+	ErrAuthorWithoutTeam   = apierrors.ErrAuthorWithoutTeam
+	ErrPRNotFound          = apierrors.ErrPRNotFound
+	ErrReassigningMergedPR = apierrors.ErrReassigningMergedPR
+	ErrUserNotAssignedToPR = apierrors.ErrUserNotAssignedToPR
+	ErrNoActiveCandidates  = apierrors.ErrNoActiveCandidates
+	ErrNoReviewers         = apierrors.ErrNoReviewers
+	ErrPeriodNotFound      = apierrors.ErrPeriodNotFound
+	ErrReviewerNotFound    = apierrors.ErrReviewerNotFound
+	ErrReviewerUnavailable = apierrors.ErrReviewerUnavailable
+	ErrTooManyRequired     = apierrors.ErrTooManyRequired
+	ErrNoSlotForCodeOwner  = apierrors.ErrNoSlotForCodeOwner
+	ErrChangingMergedPR    = apierrors.ErrChangingMergedPR
+	ErrUserAlreadyAssigned = apierrors.ErrUserAlreadyAssigned
+	ErrNotACandidate       = apierrors.ErrNotACandidate
+	ErrReviewerExcluded    = apierrors.ErrReviewerExcluded
+	ErrTooManyReviewers    = apierrors.ErrTooManyReviewers
+	ErrTooFewReviewers     = apierrors.ErrTooFewReviewers
+	ErrRateLimited         = apierrors.ErrRateLimited
+	ErrBodyTooLarge        = apierrors.ErrBodyTooLarge
+	ErrUnauthorized        = apierrors.ErrUnauthorized
+	ErrInvalidInput        = apierrors.ErrInvalidInput
+
+	ErrIdempotencyKeyReused  = apierrors.ErrIdempotencyKeyReused
+	ErrIdempotencyInProgress = apierrors.ErrIdempotencyInProgress
 )
 
-type Error struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
+// ErrUserExists is not part of the REST API, only the directory sync creates
+// users one by one.
+var ErrUserExists = errors.New("user already exists")
+
+// ErrTxConflict is returned when a transaction kept conflicting with
+// concurrent ones after all of its retries.
+var ErrTxConflict = errors.New("transaction conflicts with concurrent ones")
 
-type ErrorResponse struct {
-	Error Error `json:"error"`
-}
+type (
+	Error         = apitypes.Error
+	ErrorResponse = apitypes.ErrorResponse
+)
@@ -1,165 +1,89 @@
 package models
 
-import "fmt"
-
-type TeamMember struct {
-	UserId   string `json:"user_id" db:"id"`
-	Username string `json:"username" db:"name"`
-	IsActive *bool  `json:"is_active" db:"isactive"`
-}
-
-func (t *TeamMember) Validate() error {
-	if t.UserId == "" {
-		return fmt.Errorf("user_id is required")
-	}
-	if t.Username == "" {
-		return fmt.Errorf("username is required")
-	}
-	if t.IsActive == nil {
-		return fmt.Errorf("is_active is required")
-	}
-	return nil
-}
-
-type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
-}
-
-func (t *Team) Validate() error {
-	if t.TeamName == "" {
-		return fmt.Errorf("team_name is required")
-	}
-	if len(t.Members) == 0 {
-		return fmt.Errorf("team must contain at least one member")
-	}
-	for i, member := range t.Members {
-		if err := member.Validate(); err != nil {
-			return fmt.Errorf("invalid team member %d: %w", i, err)
-		}
-	}
-	return nil
-}
-
-type User struct {
-	UserId   string `json:"user_id" db:"id"`
-	Username string `json:"username" db:"name"`
-	TeamName string `json:"team_name" db:"team_name"`
-	IsActive bool   `json:"is_active" db:"isactive"`
-}
-
-type TeamAddResponse201 struct {
-	Team Team `json:"team"`
-}
-
-type UsersSetIsActiveRequest struct {
-	UserId   string `json:"user_id"`
-	IsActive *bool  `json:"is_active"`
-}
-
-func (u *UsersSetIsActiveRequest) Validate() error {
-	if u.UserId == "" {
-		return fmt.Errorf("user_id is required")
-	}
-	if u.IsActive == nil {
-		return fmt.Errorf("is_active is required")
-	}
-	return nil
-}
-
-type UsersSerIsActiveResponse200 struct {
-	User User `json:"user"`
-}
-
-type PullRequest struct {
-	PullRequestId     string   `json:"pull_request_id" db:"id"`
-	PullRequestName   string   `json:"pull_request_name" db:"title"`
-	AuthorId          string   `json:"author_id" db:"author_id"`
-	Status            Status   `json:"status" db:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers"`
-	CreatedAt         *string  `json:"createdAt,omitempty" db:"created_at"`
-	MergedAt          *string  `json:"mergedAt,omitempty" db:"merged_at"`
-}
-
-type PullRequestCreateRequest struct {
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorId        string `json:"author_id"`
-}
-
-func (p *PullRequestCreateRequest) Validate() error {
-	if p.PullRequestId == "" {
-		return fmt.Errorf("pull_request_id is required")
-	}
-	if p.PullRequestName == "" {
-		return fmt.Errorf("pull_request_name is required")
-	}
-	if p.AuthorId == "" {
-		return fmt.Errorf("author_id is required")
-	}
-	return nil
-}
-func (p *PullRequestCreateRequest) ToPullRequest() *PullRequest {
-	return &PullRequest{
-		PullRequestId:   p.PullRequestId,
-		PullRequestName: p.PullRequestName,
-		AuthorId:        p.AuthorId,
-	}
-}
-
-type PullRequestCreateResponse201 struct {
-	Pr PullRequest `json:"pr"`
-}
-
-type PullRequestMergeRequest struct {
-	PullRequestId string `json:"pull_request_id"`
-}
-
-func (p *PullRequestMergeRequest) Validate() error {
-	if p.PullRequestId == "" {
-		return fmt.Errorf("pull_request_id is required")
-	}
-	return nil
-}
-
-func (p *PullRequestMergeRequest) ToPullRequest() *PullRequest {
-	return &PullRequest{
-		PullRequestId: p.PullRequestId,
-	}
-}
-
-type PullRequestMergeResponse200 struct {
-	Pr PullRequest `json:"pr"`
-}
-
-type PullRequestReassignRequest struct {
-	PullRequestId string `json:"pull_request_id"`
-	OldReviewerId string `json:"old_reviewer_id"`
-}
-
-func (p *PullRequestReassignRequest) Validate() error {
-	if p.PullRequestId == "" {
-		return fmt.Errorf("pull_request_id is required")
-	}
-	if p.OldReviewerId == "" {
-		return fmt.Errorf("old_reviewer_id is required")
-	}
-	return nil
-}
-
-type PullRequestReassignResponse200 struct {
-	Pr         PullRequest `json:"pr"`
-	ReplacedBy string      `json:"replaced_by"`
-}
-
-type PullRequestShort struct {
-	PullRequestId   string `json:"pull_request_id" db:"id"`
-	PullRequestName string `json:"pull_request_name" db:"title"`
-	AuthorId        string `json:"author_id" db:"author_id"`
-	Status          Status `json:"status" db:"status"`
-}
-
-type UsersGetReviewResponse200 struct {
-	UserId       string             `json:"user_id"`
-	PullRequests []PullRequestShort `json:"pull_requests"`
+import "github.com/Sugyk/avito_test_task/pkg/apitypes"
+
+// The request and response bodies live in pkg/apitypes so that clients
+// outside this module can use them.
+type (
+	TeamMember                  = apitypes.TeamMember
+	Team                        = apitypes.Team
+	User                        = apitypes.User
+	TeamAddResponse201          = apitypes.TeamAddResponse201
+	UsersSetIsActiveRequest     = apitypes.UsersSetIsActiveRequest
+	UsersSerIsActiveResponse200 = apitypes.UsersSerIsActiveResponse200
+	UsersGetReviewResponse200   = apitypes.UsersGetReviewResponse200
+	Status                      = apitypes.Status
+
+	PullRequest                      = apitypes.PullRequest
+	PullRequestShort                 = apitypes.PullRequestShort
+	PullRequestCreateRequest         = apitypes.PullRequestCreateRequest
+	PullRequestCreateResponse201     = apitypes.PullRequestCreateResponse201
+	PullRequestMergeRequest          = apitypes.PullRequestMergeRequest
+	PullRequestMergeResponse200      = apitypes.PullRequestMergeResponse200
+	PullRequestReassignRequest       = apitypes.PullRequestReassignRequest
+	PullRequestReassignResponse200   = apitypes.PullRequestReassignResponse200
+	PullRequestReviewerRequest       = apitypes.PullRequestReviewerRequest
+	PullRequestReviewerResponse200   = apitypes.PullRequestReviewerResponse200
+	PullRequestBulkCreateRequest     = apitypes.PullRequestBulkCreateRequest
+	PullRequestBulkCreateItem        = apitypes.PullRequestBulkCreateItem
+	PullRequestBulkCreateResponse200 = apitypes.PullRequestBulkCreateResponse200
+	BulkItemStatus                   = apitypes.BulkItemStatus
+
+	Unavailability                = apitypes.Unavailability
+	UnavailabilityCreateRequest   = apitypes.UnavailabilityCreateRequest
+	UnavailabilityUpdateRequest   = apitypes.UnavailabilityUpdateRequest
+	UnavailabilityResponse        = apitypes.UnavailabilityResponse
+	UnavailabilityListResponse200 = apitypes.UnavailabilityListResponse200
+
+	UserExpertise             = apitypes.UserExpertise
+	UserExpertiseResponse200  = apitypes.UserExpertiseResponse200
+	PathRule                  = apitypes.PathRule
+	TeamPathRules             = apitypes.TeamPathRules
+	TeamPathRulesResponse200  = apitypes.TeamPathRulesResponse200
+	TeamCodeownersRequest     = apitypes.TeamCodeownersRequest
+	TeamCodeowners            = apitypes.TeamCodeowners
+	TeamCodeownersResponse200 = apitypes.TeamCodeownersResponse200
+
+	TeamSettings              = apitypes.TeamSettings
+	TeamSettingsResponse200   = apitypes.TeamSettingsResponse200
+	OverdueReview             = apitypes.OverdueReview
+	OverdueReviewsResponse200 = apitypes.OverdueReviewsResponse200
+)
+
+const (
+	StatusOpen   = apitypes.StatusOpen
+	StatusMerged = apitypes.StatusMerged
+
+	ReviewersPerPR    = apitypes.ReviewersPerPR
+	MaxBulkCreateSize = apitypes.MaxBulkCreateSize
+
+	BulkItemCreated = apitypes.BulkItemCreated
+	BulkItemFailed  = apitypes.BulkItemFailed
+	BulkItemSkipped = apitypes.BulkItemSkipped
+)
+
+var NormalizeTags = apitypes.NormalizeTags
+
+// BulkCreateResult is the outcome of one pull request of a bulk create.
+// Both fields are nil when the pull request was skipped.
+type BulkCreateResult struct {
+	Pr  *PullRequest
+	Err error
+}
+
+// ReviewAssignment is a single reviewer assigned to a pull request.
+type ReviewAssignment struct {
+	PullRequestId string `db:"pr_id"`
+	UserId        string `db:"user_id"`
+}
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key. StatusCode is nil while the request is in progress.
+type IdempotencyRecord struct {
+	Client       string `db:"client"`
+	Key          string `db:"key"`
+	Route        string `db:"route"`
+	RequestHash  string `db:"request_hash"`
+	StatusCode   *int   `db:"status_code"`
+	ResponseBody []byte `db:"response_body"`
 }
@@ -0,0 +1,13 @@
+package models
+
+// ReassignReason tells why a reviewer was replaced, it is recorded with every
+// reassignment.
+type ReassignReason string
+
+const (
+	// ReassignManual is a reassignment requested through the API.
+	ReassignManual ReassignReason = "MANUAL"
+	// ReassignStale is a reassignment of a review the reviewer kept longer
+	// than the team's stale_review_hours.
+	ReassignStale ReassignReason = "STALE_REVIEW"
+)
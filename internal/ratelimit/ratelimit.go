@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// Rule is a token bucket refilled with RPS tokens per second, holding up to
+// Burst tokens.
+type Rule struct {
+	RPS   float64
+	Burst int
+}
+
+type Config struct {
+	// Default applies to routes without a rule of their own.
+	Default Rule
+	// Routes maps mux patterns, e.g. "POST /pullRequest/create", to rules.
+	Routes map[string]Rule
+	// TrustProxy keys clients without a known token by the first
+	// X-Forwarded-For address instead of the peer address. Enable it only
+	// behind a proxy that sets the header.
+	TrustProxy bool
+	// Tokens lists the API tokens clients are keyed by. Any other bearer
+	// token is ignored, so made-up tokens cannot buy fresh buckets.
+	Tokens []string
+}
+
+// idleTTL is how long the bucket of a silent client is kept.
+const idleTTL = 10 * time.Minute
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps a token bucket per client and route.
+type Limiter struct {
+	config Config
+	tokens map[string]struct{}
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(config Config) *Limiter {
+	tokens := make(map[string]struct{}, len(config.Tokens))
+	for _, token := range config.Tokens {
+		tokens[hashToken(token)] = struct{}{}
+	}
+	return &Limiter{
+		config:  config,
+		tokens:  tokens,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *Limiter) rule(route string) Rule {
+	if rule, ok := l.config.Routes[route]; ok {
+		return rule
+	}
+	return l.config.Default
+}
+
+// allow takes a token from the bucket of client on route. When the bucket is
+// empty it returns how long until the next token.
+func (l *Limiter) allow(client, route string) (bool, time.Duration) {
+	rule := l.rule(route)
+	if rule.RPS <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	key := route + "\x00" + client
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(rule.RPS), max(rule.Burst, 1))}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep drops idle buckets, at most once per idleTTL. Callers hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ClientKey identifies the caller by its API token when the token is one of
+// Config.Tokens and by its address otherwise. Tokens are hashed so they do
+// not linger in memory in plain text.
+func (l *Limiter) ClientKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok && token != "" {
+			hash := hashToken(token)
+			if _, known := l.tokens[hash]; known {
+				return "token:" + hash
+			}
+		}
+	}
+	if l.config.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return "ip:" + strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// Middleware rejects requests over the limit of their route with 429 and a
+// Retry-After header. Routes are told apart by the mux pattern.
+func (l *Limiter) Middleware(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, route := mux.Handler(r)
+			if route == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ok, retryAfter := l.allow(l.ClientKey(r), route)
+			if !ok {
+				writeRateLimited(w, retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error: models.Error{
+			Code:    models.RateLimitedErrorCode,
+			Message: models.ErrRateLimited.Error(),
+		},
+	})
+}
+
+// MaxBytes caps request bodies at n bytes. Reading past the cap fails with
+// *http.MaxBytesError.
+func MaxBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, n)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	mux.HandleFunc("POST /pullRequest/create", ok)
+	mux.HandleFunc("GET /team/get", ok)
+	return mux
+}
+
+func TestMiddleware_LimitsPerRouteAndClient(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := New(Config{
+		Default: Rule{RPS: 100, Burst: 100},
+		Routes: map[string]Rule{
+			"POST /pullRequest/create": {RPS: 1, Burst: 2},
+		},
+		Tokens: []string{"a", "b"},
+	})
+	l.now = func() time.Time { return now }
+
+	mux := newMux()
+	h := l.Middleware(mux)(mux)
+	do := func(method, target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "a").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "a").Code)
+
+	rec := do(http.MethodPost, "/pullRequest/create", "a")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+	require.Contains(t, rec.Body.String(), `"RATE_LIMITED"`)
+
+	// other clients and routes have buckets of their own
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "b").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/team/get", "a").Code)
+
+	// the bucket refills over time
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/pullRequest/create", "a").Code)
+}
+
+func TestMiddleware_UnlimitedAndUnmatched(t *testing.T) {
+	l := New(Config{})
+	mux := newMux()
+	h := l.Middleware(mux)(mux)
+
+	for i := 0; i < 10; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestClientKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	require.Equal(t, "ip:10.0.0.1", New(Config{}).ClientKey(req))
+	require.Equal(t, "ip:203.0.113.7", New(Config{TrustProxy: true}).ClientKey(req))
+
+	req.Header.Set("Authorization", "Bearer secret")
+	key := New(Config{Tokens: []string{"secret"}}).ClientKey(req)
+	require.True(t, strings.HasPrefix(key, "token:"))
+	require.NotContains(t, key, "secret")
+
+	// unknown tokens fall back to the address
+	require.Equal(t, "ip:10.0.0.1", New(Config{}).ClientKey(req))
+	require.Equal(t, "ip:10.0.0.1", New(Config{Tokens: []string{"other"}}).ClientKey(req))
+}
+
+func TestMiddleware_UnknownTokensShareAddressBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := New(Config{Default: Rule{RPS: 1, Burst: 1}})
+	l.now = func() time.Time { return now }
+
+	mux := newMux()
+	h := l.Middleware(mux)(mux)
+	do := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, do("first"))
+	require.Equal(t, http.StatusTooManyRequests, do("second"))
+}
+
+func TestSweepDropsIdleBuckets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := New(Config{Default: Rule{RPS: 1, Burst: 1}})
+	l.now = func() time.Time { return now }
+
+	l.allow("a", "GET /team/get")
+	now = now.Add(2 * idleTTL)
+	l.allow("b", "GET /team/get")
+
+	require.Len(t, l.buckets, 1)
+}
+
+func TestMaxBytes(t *testing.T) {
+	var readErr error
+	h := MaxBytes(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345")))
+
+	var tooLarge *http.MaxBytesError
+	require.ErrorAs(t, readErr, &tooLarge)
+}
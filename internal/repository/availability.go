@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// ListUnavailability returns the unavailability periods of the user ordered
+// by start. Periods that are over are left out unless includePast is set.
+func (r *Repository) ListUnavailability(ctx context.Context, userID string, includePast bool) ([]models.Unavailability, error) {
+	periods := []models.Unavailability{}
+	listQuery := `
+	SELECT id, user_id, starts_at, ends_at, reason
+	FROM UserUnavailability
+	WHERE user_id = $1 AND ($2 OR ends_at > now())
+	ORDER BY starts_at, id
+	`
+	err := r.db.SelectContext(ctx, &periods, listQuery, userID, includePast)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting unavailability: %w", err)
+	}
+	return periods, nil
+}
+
+func (r *Repository) CreateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error) {
+	insertQuery := `
+	INSERT INTO UserUnavailability (user_id, starts_at, ends_at, reason)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, user_id, starts_at, ends_at, reason
+	`
+	err := r.db.GetContext(ctx, period, insertQuery, period.UserId, period.StartsAt, period.EndsAt, period.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("db: error inserting unavailability: %w", err)
+	}
+	return period, nil
+}
+
+// UpdateUnavailability sets the start, end and reason of the period with the
+// id of period.
+func (r *Repository) UpdateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error) {
+	updateQuery := `
+	UPDATE UserUnavailability
+	SET starts_at = $2, ends_at = $3, reason = $4
+	WHERE id = $1
+	RETURNING id, user_id, starts_at, ends_at, reason
+	`
+	err := r.db.GetContext(ctx, period, updateQuery, period.Id, period.StartsAt, period.EndsAt, period.Reason)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPeriodNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error updating unavailability: %w", err)
+	}
+	return period, nil
+}
+
+func (r *Repository) DeleteUnavailability(ctx context.Context, id int64) (*models.Unavailability, error) {
+	var period models.Unavailability
+	deleteQuery := `
+	DELETE FROM UserUnavailability
+	WHERE id = $1
+	RETURNING id, user_id, starts_at, ends_at, reason
+	`
+	err := r.db.GetContext(ctx, &period, deleteQuery, id)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPeriodNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error deleting unavailability: %w", err)
+	}
+	return &period, nil
+}
+
+// GetUnavailableUsers returns the ids of the given users who are in one of
+// their unavailability periods right now.
+func (r *Repository) GetUnavailableUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	ids := []string{}
+	selectQuery := `
+	SELECT DISTINCT user_id
+	FROM UserUnavailability
+	WHERE user_id = ANY($1) AND starts_at <= now() AND ends_at > now()
+	`
+	err := r.db.SelectContext(ctx, &ids, selectQuery, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting unavailable users: %w", err)
+	}
+	return ids, nil
+}
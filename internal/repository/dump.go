@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportDump reads all teams, users, pull requests and reviewer assignments
+// from a single snapshot of the database.
+func (r *Repository) ExportDump(ctx context.Context) (*models.Dump, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("db: error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	teamNames := []string{}
+	if err := tx.SelectContext(ctx, &teamNames, `SELECT name FROM Teams ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("db: error selecting teams: %w", err)
+	}
+	users := []models.User{}
+	usersQuery := `SELECT id, name, COALESCE(team_name, '') AS team_name, isActive FROM Users ORDER BY id`
+	if err := tx.SelectContext(ctx, &users, usersQuery); err != nil {
+		return nil, fmt.Errorf("db: error selecting users: %w", err)
+	}
+	prs := []models.PullRequest{}
+	prsQuery := `
+	SELECT id, title, author_id, status, created_at, merged_at
+	FROM PullRequests
+	ORDER BY id
+	`
+	if err := tx.SelectContext(ctx, &prs, prsQuery); err != nil {
+		return nil, fmt.Errorf("db: error selecting pull requests: %w", err)
+	}
+	assignments := []models.ReviewAssignment{}
+	if err := tx.SelectContext(ctx, &assignments, `SELECT pr_id, user_id FROM PullRequestsUsers ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("db: error selecting reviewers: %w", err)
+	}
+
+	d := &models.Dump{
+		Teams:        make([]models.Team, 0, len(teamNames)),
+		PullRequests: prs,
+	}
+	teamIndex := make(map[string]int, len(teamNames))
+	for i, name := range teamNames {
+		teamIndex[name] = i
+		d.Teams = append(d.Teams, models.Team{TeamName: name, Members: []models.TeamMember{}})
+	}
+	for _, user := range users {
+		i, ok := teamIndex[user.TeamName]
+		if !ok {
+			continue
+		}
+		isActive := user.IsActive
+		d.Teams[i].Members = append(d.Teams[i].Members, models.TeamMember{
+			UserId:   user.UserId,
+			Username: user.Username,
+			IsActive: &isActive,
+		})
+	}
+	prIndex := make(map[string]int, len(prs))
+	for i := range d.PullRequests {
+		prIndex[d.PullRequests[i].PullRequestId] = i
+		d.PullRequests[i].AssignedReviewers = []string{}
+	}
+	for _, a := range assignments {
+		pr := &d.PullRequests[prIndex[a.PullRequestId]]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, a.UserId)
+	}
+	return d, nil
+}
+
+// ImportDump creates or updates everything in d in one transaction. Reviewer
+// assignments of the imported pull requests are replaced by the ones in d:
+// only the reviewers that differ are deleted or inserted, so the assignments
+// kept are left as they are. Nothing missing from d is deleted.
+func (r *Repository) ImportDump(ctx context.Context, d *models.Dump) error {
+	insertTeamQuery := `INSERT INTO Teams (name) VALUES ($1) ON CONFLICT DO NOTHING`
+	upsertUserQuery := `
+	INSERT INTO Users (id, name, team_name, isActive)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (id) DO UPDATE SET
+	name = EXCLUDED.name,
+	team_name = EXCLUDED.team_name,
+	isActive = EXCLUDED.isActive
+	`
+	upsertPRQuery := `
+	INSERT INTO PullRequests (id, title, author_id, status, created_at, merged_at)
+	VALUES ($1, $2, $3, $4, COALESCE($5::timestamp, CURRENT_TIMESTAMP), $6::timestamp)
+	ON CONFLICT (id) DO UPDATE SET
+	title = EXCLUDED.title,
+	author_id = EXCLUDED.author_id,
+	status = EXCLUDED.status,
+	created_at = COALESCE($5::timestamp, PullRequests.created_at),
+	merged_at = EXCLUDED.merged_at
+	`
+	// $2 and $3 are the pr_id and user_id of every assignment in d
+	deleteReviewersQuery := `
+	DELETE FROM PullRequestsUsers AS pru
+	WHERE pru.pr_id = ANY($1)
+	AND NOT EXISTS (
+		SELECT 1 FROM unnest($2::VARCHAR[], $3::VARCHAR[]) AS a(pr_id, user_id)
+		WHERE a.pr_id = pru.pr_id AND a.user_id = pru.user_id
+	)
+	`
+	insertReviewersQuery := `
+	INSERT INTO PullRequestsUsers (pr_id, user_id)
+	SELECT a.pr_id, a.user_id
+	FROM unnest($1::VARCHAR[], $2::VARCHAR[]) WITH ORDINALITY AS a(pr_id, user_id, position)
+	WHERE NOT EXISTS (
+		SELECT 1 FROM PullRequestsUsers AS pru
+		WHERE pru.pr_id = a.pr_id AND pru.user_id = a.user_id
+	)
+	ORDER BY a.position
+	`
+
+	prIDs := make([]string, 0, len(d.PullRequests))
+	assignedPRIDs := make([]string, 0)
+	assignedUserIDs := make([]string, 0)
+	for _, pr := range d.PullRequests {
+		prIDs = append(prIDs, pr.PullRequestId)
+		for _, reviewer := range pr.AssignedReviewers {
+			assignedPRIDs = append(assignedPRIDs, pr.PullRequestId)
+			assignedUserIDs = append(assignedUserIDs, reviewer)
+		}
+	}
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, team := range d.Teams {
+			if _, err := tx.ExecContext(ctx, insertTeamQuery, team.TeamName); err != nil {
+				return fmt.Errorf("db: error inserting team %s: %w", team.TeamName, err)
+			}
+		}
+		for _, team := range d.Teams {
+			for _, member := range team.Members {
+				_, err := tx.ExecContext(ctx, upsertUserQuery, member.UserId, member.Username, team.TeamName, member.IsActive)
+				if err != nil {
+					return fmt.Errorf("db: error upserting user %s: %w", member.UserId, err)
+				}
+			}
+		}
+		for _, pr := range d.PullRequests {
+			_, err := tx.ExecContext(ctx, upsertPRQuery,
+				pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, pr.CreatedAt, pr.MergedAt)
+			if err != nil {
+				return fmt.Errorf("db: error upserting pull request %s: %w", pr.PullRequestId, err)
+			}
+		}
+		if len(prIDs) == 0 {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, deleteReviewersQuery, prIDs, assignedPRIDs, assignedUserIDs); err != nil {
+			return fmt.Errorf("db: error deleting reviewers: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertReviewersQuery, assignedPRIDs, assignedUserIDs); err != nil {
+			return fmt.Errorf("db: error inserting reviewers: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetExpertiseOfUsers returns the expertise tags of each of the users that
+// have some.
+func (r *Repository) GetExpertiseOfUsers(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	rows := []struct {
+		UserId string `db:"user_id"`
+		Tag    string `db:"tag"`
+	}{}
+	selectQuery := `
+	SELECT user_id, tag
+	FROM UserExpertise
+	WHERE user_id = ANY($1)
+	ORDER BY user_id, tag
+	`
+	err := r.db.SelectContext(ctx, &rows, selectQuery, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting expertise: %w", err)
+	}
+	tags := make(map[string][]string)
+	for _, row := range rows {
+		tags[row.UserId] = append(tags[row.UserId], row.Tag)
+	}
+	return tags, nil
+}
+
+// SetUserExpertise replaces the expertise tags of the user.
+func (r *Repository) SetUserExpertise(ctx context.Context, userID string, tags []string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM UserExpertise WHERE user_id = $1`, userID)
+		if err != nil {
+			return fmt.Errorf("db: error deleting expertise: %w", err)
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, `
+		INSERT INTO UserExpertise (user_id, tag)
+		SELECT $1, tag FROM unnest($2::VARCHAR[]) AS tag
+		`, userID, tags)
+		if err != nil {
+			return fmt.Errorf("db: error inserting expertise: %w", err)
+		}
+		return nil
+	})
+}
+
+type pathRuleRow struct {
+	TeamName string `db:"team_name"`
+	Position int    `db:"position"`
+	Pattern  string `db:"pattern"`
+	UserId   string `db:"user_id"`
+	Tag      string `db:"tag"`
+}
+
+// GetPathRulesOfTeams returns the path rules of each of the teams that have
+// some, in their order.
+func (r *Repository) GetPathRulesOfTeams(ctx context.Context, teamNames []string) (map[string][]models.PathRule, error) {
+	rows := []pathRuleRow{}
+	selectQuery := `
+	SELECT team_name, position, pattern, COALESCE(user_id, '') AS user_id, COALESCE(tag, '') AS tag
+	FROM TeamPathRules
+	WHERE team_name = ANY($1)
+	ORDER BY team_name, position, id
+	`
+	err := r.db.SelectContext(ctx, &rows, selectQuery, teamNames)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting path rules: %w", err)
+	}
+
+	rules := make(map[string][]models.PathRule)
+	for i, row := range rows {
+		if i == 0 || row.TeamName != rows[i-1].TeamName || row.Position != rows[i-1].Position {
+			rules[row.TeamName] = append(rules[row.TeamName], models.PathRule{
+				Pattern: row.Pattern,
+				Users:   []string{},
+				Tags:    []string{},
+			})
+		}
+		teamRules := rules[row.TeamName]
+		rule := &teamRules[len(teamRules)-1]
+		if row.UserId != "" {
+			rule.Users = append(rule.Users, row.UserId)
+		}
+		if row.Tag != "" {
+			rule.Tags = append(rule.Tags, row.Tag)
+		}
+	}
+	return rules, nil
+}
+
+func (r *Repository) GetTeamPathRules(ctx context.Context, teamName string) ([]models.PathRule, error) {
+	var checkTeamName string
+	err := r.db.GetContext(ctx, &checkTeamName, `SELECT name FROM Teams WHERE name = $1`, teamName)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error retrieving team: %w", err)
+	}
+	rules, err := r.GetPathRulesOfTeams(ctx, []string{teamName})
+	if err != nil {
+		return nil, err
+	}
+	if rules[teamName] == nil {
+		return []models.PathRule{}, nil
+	}
+	return rules[teamName], nil
+}
+
+// SetTeamPathRules replaces the path rules of the team.
+func (r *Repository) SetTeamPathRules(ctx context.Context, teamName string, rules []models.PathRule) error {
+	insertQuery := squirrel.Insert("TeamPathRules").Columns("team_name", "position", "pattern", "user_id", "tag")
+	for position, rule := range rules {
+		for _, userID := range rule.Users {
+			insertQuery = insertQuery.Values(teamName, position, rule.Pattern, userID, nil)
+		}
+		for _, tag := range rule.Tags {
+			insertQuery = insertQuery.Values(teamName, position, rule.Pattern, nil, tag)
+		}
+		if len(rule.Users) == 0 && len(rule.Tags) == 0 {
+			insertQuery = insertQuery.Values(teamName, position, rule.Pattern, nil, nil)
+		}
+	}
+	insertRulesQuery, args, err := insertQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil && len(rules) > 0 {
+		return fmt.Errorf("db: error building query: %w", err)
+	}
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var checkTeamName string
+		err := tx.GetContext(ctx, &checkTeamName, `SELECT name FROM Teams WHERE name = $1 FOR UPDATE`, teamName)
+		if err == sql.ErrNoRows {
+			return models.ErrTeamNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("db: error retrieving team: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM TeamPathRules WHERE team_name = $1`, teamName)
+		if err != nil {
+			return fmt.Errorf("db: error deleting path rules: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, insertRulesQuery, args...)
+		if err != nil {
+			return fmt.Errorf("db: error inserting path rules: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetCodeownersOfTeams returns the CODEOWNERS file of each of the teams that
+// have one.
+func (r *Repository) GetCodeownersOfTeams(ctx context.Context, teamNames []string) (map[string]string, error) {
+	rows := []struct {
+		TeamName string `db:"team_name"`
+		Content  string `db:"content"`
+	}{}
+	selectQuery := `SELECT team_name, content FROM TeamCodeowners WHERE team_name = ANY($1)`
+	err := r.db.SelectContext(ctx, &rows, selectQuery, teamNames)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting codeowners: %w", err)
+	}
+	files := make(map[string]string, len(rows))
+	for _, row := range rows {
+		files[row.TeamName] = row.Content
+	}
+	return files, nil
+}
+
+// SetTeamCodeowners replaces the CODEOWNERS file of the team, an empty
+// content removes it.
+func (r *Repository) SetTeamCodeowners(ctx context.Context, teamName string, content string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var checkTeamName string
+		err := tx.GetContext(ctx, &checkTeamName, `SELECT name FROM Teams WHERE name = $1 FOR UPDATE`, teamName)
+		if err == sql.ErrNoRows {
+			return models.ErrTeamNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("db: error retrieving team: %w", err)
+		}
+
+		if content == "" {
+			_, err = tx.ExecContext(ctx, `DELETE FROM TeamCodeowners WHERE team_name = $1`, teamName)
+			if err != nil {
+				return fmt.Errorf("db: error deleting codeowners: %w", err)
+			}
+			return nil
+		}
+		upsertQuery := `
+		INSERT INTO TeamCodeowners (team_name, content)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET content = EXCLUDED.content, updated_at = CURRENT_TIMESTAMP
+		`
+		_, err = tx.ExecContext(ctx, upsertQuery, teamName, content)
+		if err != nil {
+			return fmt.Errorf("db: error saving codeowners: %w", err)
+		}
+		return nil
+	})
+}
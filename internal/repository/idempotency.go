@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// ClaimIdempotencyKey stores an in-progress record for key of client on
+// route. If the key was already used, the existing record is returned with
+// claimed false, unless it is still in progress and was claimed before
+// staleBefore: such a claim is taken over, as its request is assumed lost.
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, client, key, route, requestHash string, staleBefore time.Time) (*models.IdempotencyRecord, bool, error) {
+	var record models.IdempotencyRecord
+	claimQuery := `
+	INSERT INTO IdempotencyKeys(client, key, route, request_hash)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (client, key, route) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash, claimed_at = CURRENT_TIMESTAMP
+	WHERE IdempotencyKeys.status_code IS NULL AND IdempotencyKeys.claimed_at < $5
+	RETURNING client, key, route, request_hash, status_code, response_body
+	`
+	err := r.db.GetContext(ctx, &record, claimQuery, client, key, route, requestHash, staleBefore)
+	if err == nil {
+		return &record, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("db: error claiming idempotency key: %w", err)
+	}
+
+	getQuery := `
+	SELECT client, key, route, request_hash, status_code, response_body
+	FROM IdempotencyKeys
+	WHERE client = $1 AND key = $2 AND route = $3
+	`
+	err = r.db.GetContext(ctx, &record, getQuery, client, key, route)
+	if err != nil {
+		return nil, false, fmt.Errorf("db: error retrieving idempotency key: %w", err)
+	}
+	return &record, false, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request holding key.
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, client, key, route string, statusCode int, body []byte) error {
+	completeQuery := `
+	UPDATE IdempotencyKeys
+	SET status_code = $4, response_body = $5
+	WHERE client = $1 AND key = $2 AND route = $3
+	`
+	_, err := r.db.ExecContext(ctx, completeQuery, client, key, route, statusCode, body)
+	if err != nil {
+		return fmt.Errorf("db: error completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets key, so that the request can be retried.
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, client, key, route string) error {
+	releaseQuery := `DELETE FROM IdempotencyKeys WHERE client = $1 AND key = $2 AND route = $3`
+	_, err := r.db.ExecContext(ctx, releaseQuery, client, key, route)
+	if err != nil {
+		return fmt.Errorf("db: error releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteIdempotencyKeysBefore removes keys created before t and returns how
+// many were removed.
+func (r *Repository) DeleteIdempotencyKeysBefore(ctx context.Context, t time.Time) (int64, error) {
+	deleteQuery := `DELETE FROM IdempotencyKeys WHERE created_at < $1`
+	res, err := r.db.ExecContext(ctx, deleteQuery, t)
+	if err != nil {
+		return 0, fmt.Errorf("db: error deleting expired idempotency keys: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -3,11 +3,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
 )
 
 func (r *Repository) GetPullRequestBase(ctx context.Context, prID string) (*models.PullRequest, error) {
@@ -37,59 +41,170 @@ func (r *Repository) GetPRReviewers(ctx context.Context, prID string) ([]string,
 	return ReviewersIds, nil
 }
 
-func (r *Repository) CreatePullRequestAndAssignReviewers(ctx context.Context, pullRequest *models.PullRequest) (_ *models.PullRequest, err error) {
-	// preparing transaction
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("db: error starting transaction: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
-				r.logger.Error("db: error while rollback commit", "error", err.Error())
-			}
-		} else {
-			if err := tx.Commit(); err != nil && err != sql.ErrTxDone {
-				r.logger.Error("db: error while commit", "error", err.Error())
-			}
-		}
-	}()
-	// end preparing transaction
-
+func (r *Repository) CreatePullRequestAndAssignReviewers(ctx context.Context, pullRequest *models.PullRequest) (*models.PullRequest, error) {
 	createPRQuery := `
 	INSERT INTO PullRequests(id, title, author_id, status)
 	VALUES ($1, $2, $3, 'OPEN')
 	RETURNING id, title, author_id, status
 	`
-	err = tx.GetContext(ctx,
-		pullRequest,
-		createPRQuery,
-		pullRequest.PullRequestId,
-		pullRequest.PullRequestName,
-		pullRequest.AuthorId,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("db: error creating pr: %w", err)
-	}
-	if len(pullRequest.AssignedReviewers) > 0 {
-		insertReviewersBuilder := squirrel.Insert("PullRequestsUsers").Columns("pr_id", "user_id")
-		for _, id := range pullRequest.AssignedReviewers {
-			insertReviewersBuilder = insertReviewersBuilder.Values(pullRequest.PullRequestId, id)
-		}
-		insertReviewersQuery, args, err := insertReviewersBuilder.PlaceholderFormat(squirrel.Dollar).ToSql()
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx,
+			pullRequest,
+			createPRQuery,
+			pullRequest.PullRequestId,
+			pullRequest.PullRequestName,
+			pullRequest.AuthorId,
+		)
 		if err != nil {
-			return nil, fmt.Errorf("db: error building query: %w", err)
+			return fmt.Errorf("db: error creating pr: %w", err)
+		}
+		if len(pullRequest.AssignedReviewers) > 0 {
+			insertReviewersBuilder := squirrel.Insert("PullRequestsUsers").Columns("pr_id", "user_id")
+			for _, id := range pullRequest.AssignedReviewers {
+				insertReviewersBuilder = insertReviewersBuilder.Values(pullRequest.PullRequestId, id)
+			}
+			insertReviewersQuery, args, err := insertReviewersBuilder.PlaceholderFormat(squirrel.Dollar).ToSql()
+			if err != nil {
+				return fmt.Errorf("db: error building query: %w", err)
+			}
+			_, err = tx.ExecContext(ctx, insertReviewersQuery, args...)
+			if err != nil {
+				return fmt.Errorf("db: error insert reviewers: %w", err)
+			}
+		}
+		return insertExclusions(ctx, tx, []*models.PullRequest{pullRequest})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest, nil
+}
+
+// insertExclusions stores the excluded reviewers of the new pull requests.
+func insertExclusions(ctx context.Context, tx *sqlx.Tx, prs []*models.PullRequest) error {
+	prIDs := make([]string, 0)
+	userIDs := make([]string, 0)
+	for _, pr := range prs {
+		for _, id := range pr.ExcludedReviewers {
+			prIDs = append(prIDs, pr.PullRequestId)
+			userIDs = append(userIDs, id)
 		}
-		_, err = tx.ExecContext(ctx, insertReviewersQuery, args...)
+	}
+	if len(prIDs) == 0 {
+		return nil
+	}
+	insertQuery := `
+	INSERT INTO PullRequestExclusions (pr_id, user_id)
+	SELECT * FROM unnest($1::VARCHAR[], $2::VARCHAR[])
+	ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, prIDs, userIDs); err != nil {
+		return fmt.Errorf("db: error inserting exclusions: %w", err)
+	}
+	return nil
+}
+
+// GetExcludedReviewers returns the users excluded from reviewing the pull
+// request when it was created.
+func (r *Repository) GetExcludedReviewers(ctx context.Context, prID string) ([]string, error) {
+	excluded := []string{}
+	err := r.db.SelectContext(ctx, &excluded, `SELECT user_id FROM PullRequestExclusions WHERE pr_id = $1`, prID)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting exclusions: %w", err)
+	}
+	return excluded, nil
+}
+
+// CreatePullRequests inserts open pull requests with their assigned and
+// excluded reviewers in a single transaction, so either all of them are
+// created or none.
+// An id that is already taken fails the whole call with ErrPRAlreadyExists.
+func (r *Repository) CreatePullRequests(ctx context.Context, prs []*models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	insertPRsBuilder := squirrel.Insert("PullRequests").Columns("id", "title", "author_id", "status")
+	insertReviewersBuilder := squirrel.Insert("PullRequestsUsers").Columns("pr_id", "user_id")
+	reviewers := 0
+	for _, pr := range prs {
+		insertPRsBuilder = insertPRsBuilder.Values(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, models.StatusOpen)
+		for _, id := range pr.AssignedReviewers {
+			insertReviewersBuilder = insertReviewersBuilder.Values(pr.PullRequestId, id)
+			reviewers++
+		}
+	}
+	insertPRsQuery, prArgs, err := insertPRsBuilder.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("db: error building query: %w", err)
+	}
+	var insertReviewersQuery string
+	var reviewerArgs []any
+	if reviewers > 0 {
+		insertReviewersQuery, reviewerArgs, err = insertReviewersBuilder.PlaceholderFormat(squirrel.Dollar).ToSql()
 		if err != nil {
-			return nil, fmt.Errorf("db: error insert reviewers: %w", err)
+			return fmt.Errorf("db: error building query: %w", err)
+		}
+	}
+
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, insertPRsQuery, prArgs...); err != nil {
+			if isUniqueViolation(err) {
+				return models.ErrPRAlreadyExists
+			}
+			return fmt.Errorf("db: error creating prs: %w", err)
+		}
+		if insertReviewersQuery != "" {
+			if _, err := tx.ExecContext(ctx, insertReviewersQuery, reviewerArgs...); err != nil {
+				return fmt.Errorf("db: error insert reviewers: %w", err)
+			}
 		}
+		return insertExclusions(ctx, tx, prs)
+	})
+	if isSerializationFailure(err) {
+		return fmt.Errorf("%w: %w", models.ErrTxConflict, err)
 	}
-	err = tx.Commit()
 	if err != nil {
-		return nil, fmt.Errorf("db: commit error: %w", err)
+		return err
 	}
-	return pullRequest, nil
+	for _, pr := range prs {
+		pr.Status = models.StatusOpen
+	}
+	return nil
+}
+
+// CountOpenReviews returns how many open pull requests each of the users
+// reviews. Users without open reviews are left out.
+func (r *Repository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	rows := []struct {
+		UserId string `db:"user_id"`
+		Count  int    `db:"count"`
+	}{}
+	countQuery := `
+	SELECT pru.user_id, COUNT(*) AS count
+	FROM PullRequestsUsers AS pru
+	JOIN PullRequests AS pr ON pr.id = pru.pr_id
+	WHERE pru.user_id = ANY($1) AND pr.status = 'OPEN'
+	GROUP BY pru.user_id
+	`
+	err := r.db.SelectContext(ctx, &rows, countQuery, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error counting open reviews: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserId] = row.Count
+	}
+	return counts, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && retryablePgCodes[pgErr.Code]
 }
 
 func (r *Repository) MergePullRequest(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
@@ -109,64 +224,244 @@ func (r *Repository) MergePullRequest(ctx context.Context, pr *models.PullReques
 	return pr, nil
 }
 
-func (r *Repository) ReAssignPullRequest(ctx context.Context, prID string, oldUser *models.User, newReviewerId string) (_ string, err error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return "", fmt.Errorf("db: start transaction error: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			if err := tx.Rollback(); err != nil {
-				r.logger.Error("db: error rollback transaction", "error", err.Error())
-			}
-		} else {
-			if err := tx.Commit(); err != nil {
-				r.logger.Error("db: error closing transaction", "error", err.Error())
-			}
-		}
-	}()
-
+// ReAssignPullRequest replaces oldUser by newReviewerId among the reviewers
+// of the pull request and records the reassignment with its reason.
+func (r *Repository) ReAssignPullRequest(ctx context.Context, prID string, oldUser *models.User, newReviewerId string, reason models.ReassignReason) (string, error) {
 	insertNewReviewerQuery := `
 	INSERT INTO PullRequestsUsers(pr_id, user_id)
 	VALUES ($1, $2)
 	RETURNING user_id
 	`
-	var checkNewReviewerID string
-	err = tx.GetContext(ctx, &checkNewReviewerID, insertNewReviewerQuery, prID, newReviewerId)
-	if err != nil {
-		return "", fmt.Errorf("db: internal error: error inserting new reviewer: %w", err)
-	}
-	if checkNewReviewerID != newReviewerId {
-		return "", fmt.Errorf("db: internal error: error inserting new reviewer: %w", err)
-	}
-
 	deleteOldReviewerQuery := `
 	DELETE FROM PullRequestsUsers
 	WHERE pr_id = $1 AND user_id = $2 
 	RETURNING pr_id, user_id
 	`
+	recordQuery := `
+	INSERT INTO ReviewerReassignments(pr_id, old_reviewer_id, new_reviewer_id, reason)
+	VALUES ($1, $2, $3, $4)
+	`
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var checkNewReviewerID string
+		err := tx.GetContext(ctx, &checkNewReviewerID, insertNewReviewerQuery, prID, newReviewerId)
+		if err != nil {
+			return fmt.Errorf("db: internal error: error inserting new reviewer: %w", err)
+		}
+		if checkNewReviewerID != newReviewerId {
+			return fmt.Errorf("db: internal error: error inserting new reviewer: got %s", checkNewReviewerID)
+		}
 
-	checkDeleted := struct {
-		CheckDeletedPRId       string `db:"pr_id"`
-		CheckDeletedReviewerId string `db:"user_id"`
-	}{}
+		checkDeleted := struct {
+			CheckDeletedPRId       string `db:"pr_id"`
+			CheckDeletedReviewerId string `db:"user_id"`
+		}{}
 
-	err = tx.GetContext(ctx, &checkDeleted, deleteOldReviewerQuery, prID, oldUser.UserId)
-	if err != nil {
-		return "", fmt.Errorf("db: internal error: error deleting old reviewer: %w", err)
-	}
-	if checkDeleted.CheckDeletedPRId != prID || checkDeleted.CheckDeletedReviewerId != oldUser.UserId {
-		return "",
-			fmt.Errorf(
+		err = tx.GetContext(ctx, &checkDeleted, deleteOldReviewerQuery, prID, oldUser.UserId)
+		if err != nil {
+			return fmt.Errorf("db: internal error: error deleting old reviewer: %w", err)
+		}
+		if checkDeleted.CheckDeletedPRId != prID || checkDeleted.CheckDeletedReviewerId != oldUser.UserId {
+			return fmt.Errorf(
 				"db: internal error: error deleting old reviewer: deleted pr_id, user_id: %s, %s. Expected: (%s, %s)",
 				checkDeleted.CheckDeletedPRId,
 				checkDeleted.CheckDeletedReviewerId,
 				prID, oldUser.UserId,
 			)
-	}
-	err = tx.Commit()
+		}
+
+		_, err = tx.ExecContext(ctx, recordQuery, prID, oldUser.UserId, newReviewerId, reason)
+		if err != nil {
+			return fmt.Errorf("db: internal error: error recording reassignment: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("db: commit error:%w", err)
+		return "", err
 	}
 	return newReviewerId, nil
 }
+
+// lockReviewers locks the open pull request for a change of its reviewers
+// and returns them in the order they were assigned.
+func lockReviewers(ctx context.Context, tx *sqlx.Tx, prID string) ([]string, error) {
+	var status models.Status
+	err := tx.GetContext(ctx, &status, `SELECT status FROM PullRequests WHERE id = $1 FOR UPDATE`, prID)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPRNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error retrieving pull request: %w", err)
+	}
+	if status != models.StatusOpen {
+		return nil, models.ErrChangingMergedPR
+	}
+	reviewers := []string{}
+	err = tx.SelectContext(ctx, &reviewers, `SELECT user_id FROM PullRequestsUsers WHERE pr_id = $1 ORDER BY id`, prID)
+	if err != nil {
+		return nil, fmt.Errorf("db: error retrieving reviewers: %w", err)
+	}
+	return reviewers, nil
+}
+
+// AddReviewer assigns the reviewer to the open pull request unless it has
+// maxReviewers reviewers already, and returns the reviewers.
+func (r *Repository) AddReviewer(ctx context.Context, prID string, reviewerID string, maxReviewers int) ([]string, error) {
+	var reviewers []string
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		reviewers, err = lockReviewers(ctx, tx, prID)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(reviewers, reviewerID) {
+			return models.ErrUserAlreadyAssigned
+		}
+		if len(reviewers) >= maxReviewers {
+			return models.ErrTooManyReviewers
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO PullRequestsUsers(pr_id, user_id) VALUES ($1, $2)`, prID, reviewerID)
+		if err != nil {
+			return fmt.Errorf("db: error inserting reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewerID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// RemoveReviewer unassigns the reviewer from the open pull request unless it
+// has minReviewers reviewers only, and returns the reviewers left.
+func (r *Repository) RemoveReviewer(ctx context.Context, prID string, reviewerID string, minReviewers int) ([]string, error) {
+	var reviewers []string
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		reviewers, err = lockReviewers(ctx, tx, prID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(reviewers, reviewerID) {
+			return models.ErrUserNotAssignedToPR
+		}
+		if len(reviewers) <= minReviewers {
+			return models.ErrTooFewReviewers
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM PullRequestsUsers WHERE pr_id = $1 AND user_id = $2`, prID, reviewerID)
+		if err != nil {
+			return fmt.Errorf("db: error deleting reviewer: %w", err)
+		}
+		reviewers = slices.DeleteFunc(reviewers, func(id string) bool {
+			return id == reviewerID
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// GetStaleReviews returns the reviews of open pull requests that stayed with
+// their reviewer longer than the stale_review_hours of the reviewer's team,
+// the oldest first.
+func (r *Repository) GetStaleReviews(ctx context.Context) ([]models.ReviewAssignment, error) {
+	reviews := []models.ReviewAssignment{}
+	staleQuery := `
+	SELECT pru.pr_id, pru.user_id
+	FROM PullRequestsUsers AS pru
+	JOIN PullRequests AS pr ON pr.id = pru.pr_id
+	JOIN Users AS u ON u.id = pru.user_id
+	JOIN Teams AS t ON t.name = u.team_name
+	WHERE pr.status = 'OPEN'
+	AND t.stale_review_hours IS NOT NULL
+	AND pru.assigned_at <= now() - make_interval(hours => t.stale_review_hours)
+	ORDER BY pru.assigned_at, pru.id
+	`
+	err := r.db.SelectContext(ctx, &reviews, staleQuery)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting stale reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// reviewDueAt is the due time of the review pru of the pull request pr by
+// the SLA of the reviewer's team t: the earliest of the first review and the
+// merge deadlines, NULL when the team has no SLA.
+const reviewDueAt = `LEAST(
+		pru.assigned_at + make_interval(hours => t.first_review_sla_hours),
+		pr.created_at::TIMESTAMPTZ + make_interval(hours => t.merge_sla_hours)
+	)`
+
+// GetOverdueReviews returns the reviews of open pull requests that are past
+// their due time, the most overdue first. An empty teamName returns the
+// reviews of all teams.
+func (r *Repository) GetOverdueReviews(ctx context.Context, teamName string) ([]models.OverdueReview, error) {
+	reviews := []models.OverdueReview{}
+	overdueQuery := fmt.Sprintf(`
+	SELECT pr_id, title, author_id, user_id, team_name, assigned_at, due_at, age_seconds
+	FROM (
+		SELECT pru.pr_id, pr.title, pr.author_id, pru.user_id, t.name AS team_name, pru.assigned_at,
+			%s AS due_at,
+			EXTRACT(EPOCH FROM now() - pru.assigned_at)::BIGINT AS age_seconds
+		FROM PullRequestsUsers AS pru
+		JOIN PullRequests AS pr ON pr.id = pru.pr_id
+		JOIN Users AS u ON u.id = pru.user_id
+		JOIN Teams AS t ON t.name = u.team_name
+		WHERE pr.status = 'OPEN'
+		AND ($1 = '' OR t.name = $1)
+	) AS reviews
+	WHERE due_at <= now()
+	ORDER BY due_at, pr_id, user_id
+	`, reviewDueAt)
+	err := r.db.SelectContext(ctx, &reviews, overdueQuery, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting overdue reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+func (r *Repository) GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error) {
+	prs := []models.PullRequest{}
+	getPRsQuery := `
+	SELECT id, title, author_id, status, created_at, merged_at
+	FROM PullRequests
+	WHERE id = ANY($1)
+	`
+	err := r.db.SelectContext(ctx, &prs, getPRsQuery, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting pull requests: %w", err)
+	}
+	return prs, nil
+}
+
+func (r *Repository) GetReviewersOfPullRequests(ctx context.Context, prIDs []string) ([]models.ReviewAssignment, error) {
+	assignments := []models.ReviewAssignment{}
+	getReviewersQuery := `
+	SELECT pr_id, user_id
+	FROM PullRequestsUsers
+	WHERE pr_id = ANY($1)
+	ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &assignments, getReviewersQuery, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting reviewers: %w", err)
+	}
+	return assignments, nil
+}
+
+func (r *Repository) GetReviewsOfUsers(ctx context.Context, userIDs []string) ([]models.ReviewAssignment, error) {
+	assignments := []models.ReviewAssignment{}
+	getReviewsQuery := `
+	SELECT pru.pr_id, pru.user_id
+	FROM PullRequestsUsers AS pru
+	WHERE pru.user_id = ANY($1)
+	ORDER BY pru.id
+	`
+	err := r.db.SelectContext(ctx, &assignments, getReviewsQuery, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting reviews: %w", err)
+	}
+	return assignments, nil
+}
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/Sugyk/avito_test_task/internal/logging"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -17,3 +19,8 @@ func NewRepository(db *sqlx.DB, logger *slog.Logger) *Repository {
 		logger,
 	}
 }
+
+// log returns the request scoped logger, falling back to the repository one.
+func (r *Repository) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, r.logger)
+}
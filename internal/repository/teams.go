@@ -7,6 +7,7 @@ import (
 
 	"github.com/Masterminds/squirrel"
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/jmoiron/sqlx"
 )
 
 func (r *Repository) GetTeamBase(ctx context.Context, team *models.Team) (*models.Team, error) {
@@ -18,32 +19,8 @@ func (r *Repository) GetTeamBase(ctx context.Context, team *models.Team) (*model
 	return team, err
 }
 
-func (r *Repository) CreateOrUpdateTeam(ctx context.Context, team *models.Team) (_ *models.Team, err error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("db: error while creating transaction")
-	}
-	defer func() {
-		if err != nil {
-			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
-				r.logger.Error("db: error while rollback changes", "error", err.Error())
-			}
-		} else {
-			if err := tx.Commit(); err != nil && err != sql.ErrTxDone {
-				r.logger.Error("db: error while rollback changes", "error", err.Error())
-			}
-		}
-	}()
-
+func (r *Repository) CreateOrUpdateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
 	insertTeamQuery := `INSERT INTO Teams (name) VALUES ($1) RETURNING name`
-	res, err := tx.ExecContext(ctx, insertTeamQuery, team.TeamName)
-	if err != nil {
-		return nil, err
-	}
-	rowsN, _ := res.RowsAffected()
-	if rowsN != 1 {
-		return nil, fmt.Errorf("db: inserting team error: affected rows expected: 1, got: %d", rowsN)
-	}
 
 	insertQuery := squirrel.Insert("Users").Columns("id", "name", "team_name", "isActive")
 	if len(team.Members) > 0 {
@@ -59,19 +36,31 @@ func (r *Repository) CreateOrUpdateTeam(ctx context.Context, team *models.Team)
 		isActive = EXCLUDED.isActive
 		`,
 	)
-	insertTeamQuery, args, err := insertQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	insertMembersQuery, args, err := insertQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("db: error building query: %w", err)
 	}
-	res, err = tx.ExecContext(ctx, insertTeamQuery, args...)
-	if err != nil {
-		return nil, err
-	}
-	rowsN, _ = res.RowsAffected()
-	if int(rowsN) != len(team.Members) {
-		return nil, fmt.Errorf("db: inserting team error: affected rows expected: %d, got: %d", len(team.Members), rowsN)
-	}
-	err = tx.Commit()
+
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, insertTeamQuery, team.TeamName)
+		if err != nil {
+			return err
+		}
+		rowsN, _ := res.RowsAffected()
+		if rowsN != 1 {
+			return fmt.Errorf("db: inserting team error: affected rows expected: 1, got: %d", rowsN)
+		}
+
+		res, err = tx.ExecContext(ctx, insertMembersQuery, args...)
+		if err != nil {
+			return err
+		}
+		rowsN, _ = res.RowsAffected()
+		if int(rowsN) != len(team.Members) {
+			return fmt.Errorf("db: inserting team error: affected rows expected: %d, got: %d", len(team.Members), rowsN)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -105,3 +94,123 @@ func (r *Repository) GetTeam(ctx context.Context, teamName string) (*models.Team
 
 	return &team, nil
 }
+
+func (r *Repository) GetTeamNames(ctx context.Context) ([]string, error) {
+	teamNames := []string{}
+	err := r.db.SelectContext(ctx, &teamNames, `SELECT name FROM Teams ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting teams: %w", err)
+	}
+	return teamNames, nil
+}
+
+// CreateTeamWithMembers creates the team and moves the existing users with
+// the given ids into it.
+func (r *Repository) CreateTeamWithMembers(ctx context.Context, teamName string, userIDs []string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO Teams (name) VALUES ($1)`, teamName)
+		if isUniqueViolation(err) {
+			return models.ErrTeamExists
+		}
+		if err != nil {
+			return fmt.Errorf("db: error inserting team: %w", err)
+		}
+		return addTeamMembers(ctx, tx, teamName, userIDs)
+	})
+}
+
+// UpdateTeamMembers moves the users in add into the team and takes the users
+// in remove out of it, leaving them without a team. With replace all other
+// members are taken out as well.
+func (r *Repository) UpdateTeamMembers(ctx context.Context, teamName string, add, remove []string, replace bool) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var name string
+		err := tx.GetContext(ctx, &name, `SELECT name FROM Teams WHERE name = $1 FOR UPDATE`, teamName)
+		if err == sql.ErrNoRows {
+			return models.ErrTeamNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("db: error retrieving team: %w", err)
+		}
+
+		if replace {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE Users SET team_name = NULL WHERE team_name = $1 AND NOT (id = ANY($2))`, teamName, add)
+		} else if len(remove) > 0 {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE Users SET team_name = NULL WHERE team_name = $1 AND id = ANY($2)`, teamName, remove)
+		}
+		if err != nil {
+			return fmt.Errorf("db: error removing team members: %w", err)
+		}
+		return addTeamMembers(ctx, tx, teamName, add)
+	})
+}
+
+func addTeamMembers(ctx context.Context, tx *sqlx.Tx, teamName string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE Users SET team_name = $1 WHERE id = ANY($2)`, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("db: error adding team members: %w", err)
+	}
+	if n, _ := res.RowsAffected(); int(n) != len(userIDs) {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteTeam deletes the team, leaving its members without a team.
+func (r *Repository) DeleteTeam(ctx context.Context, teamName string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `UPDATE Users SET team_name = NULL WHERE team_name = $1`, teamName); err != nil {
+			return fmt.Errorf("db: error removing team members: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, `DELETE FROM Teams WHERE name = $1`, teamName)
+		if err != nil {
+			return fmt.Errorf("db: error deleting team: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return models.ErrTeamNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	settingsQuery := `
+	SELECT name, stale_review_hours, first_review_sla_hours, merge_sla_hours, min_reviewers, max_reviewers
+	FROM Teams
+	WHERE name = $1
+	`
+	err := r.db.GetContext(ctx, &settings, settingsQuery, teamName)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error retrieving team settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *Repository) UpdateTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error) {
+	updateQuery := `
+	UPDATE Teams
+	SET stale_review_hours = $2, first_review_sla_hours = $3, merge_sla_hours = $4,
+		min_reviewers = $5, max_reviewers = $6
+	WHERE name = $1
+	RETURNING name, stale_review_hours, first_review_sla_hours, merge_sla_hours, min_reviewers, max_reviewers
+	`
+	err := r.db.GetContext(ctx, settings, updateQuery,
+		settings.TeamName, settings.StaleReviewHours, settings.FirstReviewSLAHours, settings.MergeSLAHours,
+		settings.MinReviewers, settings.MaxReviewers)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db: error updating team settings: %w", err)
+	}
+	return settings, nil
+}
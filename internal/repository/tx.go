@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/rand"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	txMaxAttempts = 3
+	txBaseBackoff = 20 * time.Millisecond
+)
+
+// postgres error codes after which the whole transaction can be run again
+var retryablePgCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// commitError marks a COMMIT that failed without an answer from the server.
+// Whether the transaction was applied is unknown then, so it is retried only
+// if the COMMIT was never sent.
+type commitError struct {
+	err error
+}
+
+func (e *commitError) Error() string { return fmt.Sprintf("db: commit error: %v", e.err) }
+
+func (e *commitError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return retryablePgCodes[pgErr.Code]
+	}
+	var commitErr *commitError
+	if errors.As(err, &commitErr) {
+		return pgconn.SafeToRetry(commitErr.err)
+	}
+	return pgconn.SafeToRetry(err) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// withTx runs fn in a transaction and commits it if fn succeeds. The whole
+// transaction is run again, with backoff, after transient failures such as
+// serialization failures, deadlocks and reset connections, so fn must not
+// have side effects outside of tx.
+func (r *Repository) withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = r.runTx(ctx, fn)
+		if err == nil || attempt == txMaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		backoff := txBaseBackoff << (attempt - 1)
+		backoff += time.Duration(rand.Int63n(int64(backoff)))
+		r.log(ctx).Warn("db: retrying transaction", "attempt", attempt, "backoff", backoff, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (r *Repository) runTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("db: error starting transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			r.log(ctx).Error("db: error while rollback changes", "error", rbErr.Error())
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			return fmt.Errorf("db: commit error: %w", err)
+		}
+		return &commitError{err: err}
+	}
+	return nil
+}
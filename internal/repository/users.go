@@ -10,7 +10,7 @@ import (
 
 func (r *Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
-	getUserQuery := `SELECT id, name, team_name, isActive FROM Users WHERE id = $1`
+	getUserQuery := `SELECT id, name, COALESCE(team_name, '') AS team_name, isActive FROM Users WHERE id = $1`
 
 	err := r.db.GetContext(ctx, &user, getUserQuery, id)
 	if err == sql.ErrNoRows {
@@ -59,11 +59,17 @@ func (r *Repository) GetUsersReview(ctx context.Context, userID string) ([]model
 		return nil, fmt.Errorf("db: error retrieving user: %w", err)
 	}
 
-	getPrsQuery := `
-	SELECT pr_id AS id, title, author_id, status FROM PullRequestsUsers
+	getPrsQuery := fmt.Sprintf(`
+	SELECT pr_id AS id, title, author_id, status,
+		%[1]s AS due_at,
+		COALESCE(status = 'OPEN' AND %[1]s <= now(), false) AS overdue
+	FROM PullRequestsUsers AS pru
 	LEFT JOIN PullRequests AS pr ON pr_id = pr.id
+	LEFT JOIN Users AS u ON u.id = pru.user_id
+	LEFT JOIN Teams AS t ON t.name = u.team_name
 	WHERE user_id = $1
-	`
+	ORDER BY pru.id
+	`, reviewDueAt)
 	err = r.db.SelectContext(ctx, &shortPRs, getPrsQuery, userID)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("db: error selecting PRs of user: %w", err)
@@ -84,3 +90,68 @@ func (r *Repository) GetActiveTeamMembersIds(ctx context.Context, team_name stri
 
 	return activeTeamMembersIds, nil
 }
+
+func (r *Repository) GetUsersByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	users := []models.User{}
+	getUsersQuery := `SELECT id, name, COALESCE(team_name, '') AS team_name, isActive FROM Users WHERE id = ANY($1)`
+	err := r.db.SelectContext(ctx, &users, getUsersQuery, ids)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *Repository) GetMembersOfTeams(ctx context.Context, teamNames []string) ([]models.User, error) {
+	members := []models.User{}
+	getMembersQuery := `
+	SELECT id, name, team_name, isActive FROM Users
+	WHERE team_name = ANY($1)
+	ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &members, getMembersQuery, teamNames)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting teams members: %w", err)
+	}
+	return members, nil
+}
+
+// ListUsers returns all users. Users without a team, which the directory
+// sync leaves behind, have an empty TeamName.
+func (r *Repository) ListUsers(ctx context.Context) ([]models.User, error) {
+	users := []models.User{}
+	listUsersQuery := `SELECT id, name, COALESCE(team_name, '') AS team_name, isActive FROM Users ORDER BY id`
+	err := r.db.SelectContext(ctx, &users, listUsersQuery)
+	if err != nil {
+		return nil, fmt.Errorf("db: error selecting users: %w", err)
+	}
+	return users, nil
+}
+
+// InsertUser creates the user, in no team when TeamName is empty. It returns
+// false when a user with the id already exists.
+func (r *Repository) InsertUser(ctx context.Context, user *models.User) (bool, error) {
+	insertUserQuery := `
+	INSERT INTO Users (id, name, team_name, isActive)
+	VALUES ($1, $2, NULLIF($3, ''), $4)
+	ON CONFLICT (id) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, insertUserQuery, user.UserId, user.Username, user.TeamName, user.IsActive)
+	if err != nil {
+		return false, fmt.Errorf("db: error inserting user: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+// UpdateUser sets the name and the activity flag of the user.
+func (r *Repository) UpdateUser(ctx context.Context, user *models.User) error {
+	updateUserQuery := `UPDATE Users SET name = $2, isActive = $3 WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, updateUserQuery, user.UserId, user.Username, user.IsActive)
+	if err != nil {
+		return fmt.Errorf("db: error updating user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package scim
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// memoryDirectory is a Directory keeping users and teams in memory, with the
+// semantics of the service.
+type memoryDirectory struct {
+	users map[string]models.User
+	teams map[string]bool
+}
+
+func newMemoryDirectory() *memoryDirectory {
+	return &memoryDirectory{users: map[string]models.User{}, teams: map[string]bool{}}
+}
+
+func (d *memoryDirectory) ListUsers(_ context.Context) ([]models.User, error) {
+	users := make([]models.User, 0, len(d.users))
+	for _, user := range d.users {
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return strings.Compare(a.UserId, b.UserId) })
+	return users, nil
+}
+
+func (d *memoryDirectory) GetUser(_ context.Context, userID string) (*models.User, error) {
+	user, ok := d.users[userID]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (d *memoryDirectory) CreateUser(_ context.Context, user *models.User) (*models.User, error) {
+	if _, ok := d.users[user.UserId]; ok {
+		return nil, models.ErrUserExists
+	}
+	user.TeamName = ""
+	d.users[user.UserId] = *user
+	return user, nil
+}
+
+func (d *memoryDirectory) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	stored, ok := d.users[user.UserId]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	stored.Username = user.Username
+	stored.IsActive = user.IsActive
+	d.users[user.UserId] = stored
+	return d.GetUser(ctx, user.UserId)
+}
+
+func (d *memoryDirectory) ListTeams(ctx context.Context) ([]models.Team, error) {
+	names := make([]string, 0, len(d.teams))
+	for name := range d.teams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	teams := make([]models.Team, 0, len(names))
+	for _, name := range names {
+		team, _ := d.GetTeamWithMembers(ctx, name)
+		teams = append(teams, *team)
+	}
+	return teams, nil
+}
+
+func (d *memoryDirectory) GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error) {
+	if !d.teams[teamName] {
+		return nil, models.ErrTeamNotFound
+	}
+	users, _ := d.ListUsers(ctx)
+	team := &models.Team{TeamName: teamName, Members: []models.TeamMember{}}
+	for _, user := range users {
+		if user.TeamName == teamName {
+			isActive := user.IsActive
+			team.Members = append(team.Members, models.TeamMember{UserId: user.UserId, Username: user.Username, IsActive: &isActive})
+		}
+	}
+	return team, nil
+}
+
+func (d *memoryDirectory) CreateTeamWithMembers(ctx context.Context, teamName string, userIDs []string) (*models.Team, error) {
+	if d.teams[teamName] {
+		return nil, models.ErrTeamExists
+	}
+	if err := d.checkUsers(userIDs); err != nil {
+		return nil, err
+	}
+	d.teams[teamName] = true
+	d.setTeam(userIDs, teamName)
+	return d.GetTeamWithMembers(ctx, teamName)
+}
+
+func (d *memoryDirectory) UpdateTeamMembers(ctx context.Context, teamName string, add, remove []string, replace bool) (*models.Team, error) {
+	if !d.teams[teamName] {
+		return nil, models.ErrTeamNotFound
+	}
+	if err := d.checkUsers(add); err != nil {
+		return nil, err
+	}
+	for id, user := range d.users {
+		if user.TeamName == teamName && (slices.Contains(remove, id) || replace && !slices.Contains(add, id)) {
+			d.setTeam([]string{id}, "")
+		}
+	}
+	d.setTeam(add, teamName)
+	return d.GetTeamWithMembers(ctx, teamName)
+}
+
+func (d *memoryDirectory) DeleteTeam(_ context.Context, teamName string) error {
+	if !d.teams[teamName] {
+		return models.ErrTeamNotFound
+	}
+	for id, user := range d.users {
+		if user.TeamName == teamName {
+			d.setTeam([]string{id}, "")
+		}
+	}
+	delete(d.teams, teamName)
+	return nil
+}
+
+func (d *memoryDirectory) checkUsers(ids []string) error {
+	for _, id := range ids {
+		if _, ok := d.users[id]; !ok {
+			return models.ErrUserNotFound
+		}
+	}
+	return nil
+}
+
+func (d *memoryDirectory) setTeam(ids []string, teamName string) {
+	for _, id := range ids {
+		user := d.users[id]
+		user.TeamName = teamName
+		d.users[id] = user
+	}
+}
@@ -0,0 +1,42 @@
+package scim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filter is an `attribute eq "value"` expression, the only kind identity
+// providers send to look up a user or a group before creating it.
+type filter struct {
+	attribute string
+	value     string
+}
+
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9.]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// parseFilter parses the filter query parameter. Attribute names are case
+// insensitive, so they are lowered. An empty expression is a nil filter.
+func parseFilter(expr string, attributes ...string) (*filter, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	m := filterPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported filter %q, only 'attribute eq \"value\"' is supported", expr)
+	}
+	attribute := strings.ToLower(m[1])
+	supported := false
+	for _, a := range attributes {
+		supported = supported || strings.EqualFold(a, attribute)
+	}
+	if !supported {
+		return nil, fmt.Errorf("filtering by %s is not supported, use one of %s", m[1], strings.Join(attributes, ", "))
+	}
+	value, err := strconv.Unquote(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %s", m[2])
+	}
+	return &filter{attribute: attribute, value: value}, nil
+}
@@ -0,0 +1,288 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+// Group is the SCIM group resource, a team.
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+func toSCIMGroup(team *models.Team, withMembers bool) Group {
+	group := Group{
+		Schemas:     []string{groupSchema},
+		ID:          team.TeamName,
+		DisplayName: team.TeamName,
+		Meta:        &Meta{ResourceType: "Group", Location: location("Groups", team.TeamName)},
+	}
+	if withMembers {
+		for _, member := range team.Members {
+			group.Members = append(group.Members, Ref{
+				Value:   member.UserId,
+				Display: member.Username,
+				Ref:     location("Users", member.UserId),
+			})
+		}
+	}
+	return group
+}
+
+// withMembers tells if the response may include the members, which identity
+// providers exclude when they only look a group up.
+func withMembers(r *http.Request) bool {
+	for _, attribute := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+func memberIDs(refs []Ref) []string {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.Value)
+	}
+	return ids
+}
+
+// sendMembershipError answers a change of members. Unknown members are an
+// error in the request, not a missing group.
+func (h *Handler) sendMembershipError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, models.ErrUserNotFound) {
+		h.sendError(w, r, http.StatusBadRequest, "invalidValue", fmt.Errorf("member: %w", err))
+		return
+	}
+	h.sendServiceError(w, r, err)
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query().Get("filter"), "displayName", "id")
+	if err != nil {
+		h.sendError(w, r, http.StatusBadRequest, "invalidFilter", err)
+		return
+	}
+	teams, err := h.dir.ListTeams(r.Context())
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	resources := make([]any, 0, len(teams))
+	for _, team := range teams {
+		if f != nil && team.TeamName != f.value {
+			continue
+		}
+		resources = append(resources, toSCIMGroup(&team, withMembers(r)))
+	}
+	h.sendList(w, r, resources)
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	team, err := h.dir.GetTeamWithMembers(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMGroup(team, withMembers(r)))
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	var req Group
+	if !h.decode(w, r, &req) {
+		return
+	}
+	if req.DisplayName == "" {
+		h.sendError(w, r, http.StatusBadRequest, "invalidValue", errors.New("displayName is required"))
+		return
+	}
+	team, err := h.dir.CreateTeamWithMembers(r.Context(), req.DisplayName, memberIDs(req.Members))
+	if err != nil {
+		h.sendMembershipError(w, r, err)
+		return
+	}
+	group := toSCIMGroup(team, true)
+	w.Header().Set("Location", group.Meta.Location)
+	h.sendJSON(w, r, http.StatusCreated, group)
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	var req Group
+	if !h.decode(w, r, &req) {
+		return
+	}
+	id := r.PathValue("id")
+	if req.DisplayName != "" && req.DisplayName != id {
+		h.sendError(w, r, http.StatusBadRequest, "mutability", errors.New("groups can not be renamed"))
+		return
+	}
+	team, err := h.dir.UpdateTeamMembers(r.Context(), id, memberIDs(req.Members), nil, true)
+	if err != nil {
+		h.sendMembershipError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMGroup(team, true))
+}
+
+// membersChange collects the member operations of a PATCH, so that they are
+// applied in one go.
+type membersChange struct {
+	add    map[string]bool
+	remove map[string]bool
+	// replace is set once the whole member list was replaced, add then
+	// holds the complete new list
+	replace bool
+}
+
+func (c *membersChange) addMember(id string) {
+	c.add[id] = true
+	delete(c.remove, id)
+}
+
+func (c *membersChange) removeMember(id string) {
+	delete(c.add, id)
+	if !c.replace {
+		c.remove[id] = true
+	}
+}
+
+func (c *membersChange) replaceMembers(ids []string) {
+	c.add = make(map[string]bool, len(ids))
+	c.remove = make(map[string]bool)
+	c.replace = true
+	for _, id := range ids {
+		c.add[id] = true
+	}
+}
+
+func keys(set map[string]bool) []string {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// memberFilterPath matches paths like members[value eq "u1"].
+var memberFilterPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"((?:[^"\\]|\\.)*)"\s*\]$`)
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	var req patchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	id := r.PathValue("id")
+	change := &membersChange{add: map[string]bool{}, remove: map[string]bool{}}
+	for _, op := range req.Operations {
+		if err := applyGroupOperation(id, change, op); err != nil {
+			scimType := "invalidValue"
+			if errors.Is(err, errRename) {
+				scimType = "mutability"
+			}
+			h.sendError(w, r, http.StatusBadRequest, scimType, err)
+			return
+		}
+	}
+
+	team, err := h.dir.UpdateTeamMembers(r.Context(), id, keys(change.add), keys(change.remove), change.replace)
+	if err != nil {
+		h.sendMembershipError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMGroup(team, true))
+}
+
+var errRename = errors.New("groups can not be renamed")
+
+func applyGroupOperation(teamName string, change *membersChange, op patchOperation) error {
+	path := strings.ToLower(op.Path)
+	if m := memberFilterPath.FindStringSubmatch(op.Path); m != nil {
+		if strings.ToLower(op.Op) != "remove" {
+			return fmt.Errorf("unsupported operation %q on a member", op.Op)
+		}
+		var id string
+		if err := json.Unmarshal([]byte(`"`+m[1]+`"`), &id); err != nil {
+			return fmt.Errorf("invalid path %q", op.Path)
+		}
+		change.removeMember(id)
+		return nil
+	}
+
+	switch {
+	case path == "":
+		// the attributes are in an object, as in a PUT
+		var attributes struct {
+			DisplayName *string `json:"displayName"`
+			Members     *[]Ref  `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return fmt.Errorf("value of an operation without path must be an object: %w", err)
+		}
+		if attributes.DisplayName != nil && *attributes.DisplayName != teamName {
+			return errRename
+		}
+		if attributes.Members != nil {
+			return applyMembersOperation(change, op.Op, *attributes.Members)
+		}
+		return nil
+	case path == "displayname":
+		var name string
+		if err := json.Unmarshal(op.Value, &name); err != nil || name != teamName {
+			return errRename
+		}
+		return nil
+	case path == "members":
+		var refs []Ref
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &refs); err != nil {
+				return fmt.Errorf("members must be a list: %w", err)
+			}
+		}
+		// removing members without a value removes all of them
+		if strings.ToLower(op.Op) == "remove" && len(op.Value) == 0 {
+			change.replaceMembers(nil)
+			return nil
+		}
+		return applyMembersOperation(change, op.Op, refs)
+	default:
+		return fmt.Errorf("unsupported path %q on a group", op.Path)
+	}
+}
+
+func applyMembersOperation(change *membersChange, op string, refs []Ref) error {
+	switch strings.ToLower(op) {
+	case "add":
+		for _, ref := range refs {
+			change.addMember(ref.Value)
+		}
+	case "remove":
+		for _, ref := range refs {
+			change.removeMember(ref.Value)
+		}
+	case "replace":
+		change.replaceMembers(memberIDs(refs))
+	default:
+		return fmt.Errorf("unsupported operation %q", op)
+	}
+	return nil
+}
+
+// deleteGroup deletes the team. Its members stay, without a team.
+func (h *Handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.dir.DeleteTeam(r.Context(), r.PathValue("id")); err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,237 @@
+// Package scim serves the SCIM 2.0 Users and Groups endpoints (RFC 7643,
+// RFC 7644), so that the identity provider of the corporate directory can
+// push users and team membership.
+//
+// Groups are teams and are identified by their displayName, the team name.
+// Users are identified by their userName, which becomes the user_id, and
+// their active attribute is the activity flag. A user belongs to at most one
+// team: adding it to a group moves it out of its previous one.
+package scim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/logging"
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+const (
+	// BasePath is where the endpoints are served.
+	BasePath    = "/scim/v2"
+	ContentType = "application/scim+json"
+
+	userSchema     = "urn:ietf:params:scim:schemas:core:2.0:User"
+	groupSchema    = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	listSchema     = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	patchSchema    = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	errorSchema    = "urn:ietf:params:scim:api:messages:2.0:Error"
+	providerSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+// Directory is the part of the service the endpoints work on.
+type Directory interface {
+	ListUsers(ctx context.Context) ([]models.User, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) (*models.User, error)
+	ListTeams(ctx context.Context) ([]models.Team, error)
+	GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error)
+	CreateTeamWithMembers(ctx context.Context, teamName string, userIDs []string) (*models.Team, error)
+	UpdateTeamMembers(ctx context.Context, teamName string, add, remove []string, replace bool) (*models.Team, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+}
+
+type Handler struct {
+	dir    Directory
+	token  string
+	logger *slog.Logger
+	mux    *http.ServeMux
+}
+
+// NewHandler serves the endpoints under BasePath. Requests must carry token
+// as a Bearer token, unless token is empty.
+func NewHandler(dir Directory, token string, logger *slog.Logger) *Handler {
+	h := &Handler{
+		dir:    dir,
+		token:  token,
+		logger: logger,
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+BasePath+"/ServiceProviderConfig", h.serviceProviderConfig)
+	h.mux.HandleFunc("GET "+BasePath+"/Users", h.listUsers)
+	h.mux.HandleFunc("POST "+BasePath+"/Users", h.createUser)
+	h.mux.HandleFunc("GET "+BasePath+"/Users/{id}", h.getUser)
+	h.mux.HandleFunc("PUT "+BasePath+"/Users/{id}", h.replaceUser)
+	h.mux.HandleFunc("PATCH "+BasePath+"/Users/{id}", h.patchUser)
+	h.mux.HandleFunc("DELETE "+BasePath+"/Users/{id}", h.deleteUser)
+	h.mux.HandleFunc("GET "+BasePath+"/Groups", h.listGroups)
+	h.mux.HandleFunc("POST "+BasePath+"/Groups", h.createGroup)
+	h.mux.HandleFunc("GET "+BasePath+"/Groups/{id}", h.getGroup)
+	h.mux.HandleFunc("PUT "+BasePath+"/Groups/{id}", h.replaceGroup)
+	h.mux.HandleFunc("PATCH "+BasePath+"/Groups/{id}", h.patchGroup)
+	h.mux.HandleFunc("DELETE "+BasePath+"/Groups/{id}", h.deleteGroup)
+	h.mux.HandleFunc(BasePath+"/", func(w http.ResponseWriter, r *http.Request) {
+		h.sendError(w, r, http.StatusNotFound, "", errors.New("endpoint not found"))
+	})
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			h.sendError(w, r, http.StatusUnauthorized, "", errors.New("invalid bearer token"))
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+// Ref points to a group from a user or to a user from a group.
+type Ref struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func (h *Handler) log(r *http.Request) *slog.Logger {
+	return logging.FromContext(r.Context(), h.logger)
+}
+
+func (h *Handler) sendJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log(r).Error("failed to encode SCIM response", "error", err.Error())
+	}
+}
+
+// sendError answers with a SCIM error. scimType is one of the detail error
+// types of RFC 7644 section 3.12, or empty.
+func (h *Handler) sendError(w http.ResponseWriter, r *http.Request, status int, scimType string, err error) {
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	h.log(r).Log(r.Context(), level, "scim request error", "status", status, "scim_type", scimType, "error", err.Error())
+	h.sendJSON(w, r, status, errorResponse{
+		Schemas:  []string{errorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   err.Error(),
+	})
+}
+
+// sendServiceError answers with the status matching an error of the
+// Directory.
+func (h *Handler) sendServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrUserExists), errors.Is(err, models.ErrTeamExists):
+		h.sendError(w, r, http.StatusConflict, "uniqueness", err)
+	case errors.Is(err, models.ErrUserNotFound), errors.Is(err, models.ErrTeamNotFound):
+		h.sendError(w, r, http.StatusNotFound, "", err)
+	default:
+		h.log(r).Error("scim: internal error", "error", err.Error())
+		h.sendError(w, r, http.StatusInternalServerError, "", models.ErrInternalError)
+	}
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.sendError(w, r, http.StatusRequestEntityTooLarge, "", models.ErrBodyTooLarge)
+			return false
+		}
+		h.sendError(w, r, http.StatusBadRequest, "invalidSyntax", err)
+		return false
+	}
+	return true
+}
+
+// sendList answers with the page of resources selected by the startIndex and
+// count query parameters.
+func (h *Handler) sendList(w http.ResponseWriter, r *http.Request, resources []any) {
+	startIndex, count := 1, defaultCount
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.sendError(w, r, http.StatusBadRequest, "invalidValue", errors.New("startIndex must be a number"))
+			return
+		}
+		startIndex = max(n, 1)
+	}
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.sendError(w, r, http.StatusBadRequest, "invalidValue", errors.New("count must be a number"))
+			return
+		}
+		count = min(max(n, 0), maxCount)
+	}
+
+	total := len(resources)
+	from := min(startIndex-1, total)
+	to := min(from+count, total)
+	h.sendJSON(w, r, http.StatusOK, listResponse{
+		Schemas:      []string{listSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: to - from,
+		Resources:    append([]any{}, resources[from:to]...),
+	})
+}
+
+func (h *Handler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(ok bool) map[string]any { return map[string]any{"supported": ok} }
+	h.sendJSON(w, r, http.StatusOK, map[string]any{
+		"schemas":        []string{providerSchema},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": maxCount},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with a static bearer token",
+		}},
+		"meta": Meta{ResourceType: "ServiceProviderConfig", Location: BasePath + "/ServiceProviderConfig"},
+	})
+}
+
+func location(resource, id string) string {
+	return BasePath + "/" + resource + "/" + url.PathEscape(id)
+}
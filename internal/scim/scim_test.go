@@ -0,0 +1,251 @@
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret-token"
+
+// exchange is a request of an identity provider and the expected response.
+// The expected body is matched as a subset of the actual one, a null
+// attribute must be absent.
+type exchange struct {
+	Name    string `json:"name"`
+	Request struct {
+		Method string          `json:"method"`
+		Path   string          `json:"path"`
+		Body   json.RawMessage `json:"body"`
+	} `json:"request"`
+	Response struct {
+		Status   int             `json:"status"`
+		Location string          `json:"location"`
+		Body     json.RawMessage `json:"body"`
+	} `json:"response"`
+}
+
+func newTestHandler() *Handler {
+	return NewHandler(newMemoryDirectory(), testToken, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func serve(h http.Handler, method, path string, body []byte, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", ContentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// TestRecordedSessions replays provisioning sessions recorded from identity
+// providers, each against an empty directory.
+func TestRecordedSessions(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var session []exchange
+			require.NoError(t, json.Unmarshal(data, &session))
+
+			h := newTestHandler()
+			for _, ex := range session {
+				w := serve(h, ex.Request.Method, ex.Request.Path, ex.Request.Body, testToken)
+				require.Equal(t, ex.Response.Status, w.Code, "%s: %s", ex.Name, w.Body.String())
+				if ex.Response.Location != "" {
+					assert.Equal(t, ex.Response.Location, w.Header().Get("Location"), ex.Name)
+				}
+				if len(ex.Response.Body) == 0 {
+					continue
+				}
+				assert.Equal(t, ContentType, w.Header().Get("Content-Type"), ex.Name)
+
+				var want, got any
+				require.NoError(t, json.Unmarshal(ex.Response.Body, &want))
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got), ex.Name)
+				assertSubset(t, ex.Name, "", want, got)
+			}
+		})
+	}
+}
+
+func assertSubset(t *testing.T, name, path string, want, got any) {
+	t.Helper()
+	switch want := want.(type) {
+	case map[string]any:
+		obj, ok := got.(map[string]any)
+		if !assert.True(t, ok, "%s: %s: expected an object, got %v", name, path, got) {
+			return
+		}
+		for key, value := range want {
+			actual, present := obj[key]
+			if value == nil {
+				assert.False(t, present && actual != nil, "%s: %s.%s: expected no value, got %v", name, path, key, actual)
+				continue
+			}
+			if assert.True(t, present, "%s: %s.%s: missing", name, path, key) {
+				assertSubset(t, name, path+"."+key, value, actual)
+			}
+		}
+	case []any:
+		list, ok := got.([]any)
+		if !assert.True(t, ok, "%s: %s: expected a list, got %v", name, path, got) ||
+			!assert.Len(t, list, len(want), "%s: %s", name, path) {
+			return
+		}
+		for i := range want {
+			assertSubset(t, name, path+"["+strconv.Itoa(i)+"]", want[i], list[i])
+		}
+	default:
+		assert.Equal(t, want, got, "%s: %s", name, path)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	h := newTestHandler()
+
+	for _, token := range []string{"", "wrong"} {
+		w := serve(h, http.MethodGet, BasePath+"/Users", nil, token)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), errorSchema)
+	}
+	w := serve(h, http.MethodGet, BasePath+"/Users", nil, testToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServiceProviderConfig(t *testing.T) {
+	w := serve(newTestHandler(), http.MethodGet, BasePath+"/ServiceProviderConfig", nil, testToken)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var config map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &config))
+	assert.Equal(t, map[string]any{"supported": true}, config["patch"])
+}
+
+func TestUnknownEndpoint(t *testing.T) {
+	w := serve(newTestHandler(), http.MethodGet, BasePath+"/Schemas", nil, testToken)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), errorSchema)
+}
+
+func TestListPagination(t *testing.T) {
+	h := newTestHandler()
+	for _, id := range []string{"u1", "u2", "u3"} {
+		body := []byte(`{"schemas":["` + userSchema + `"],"userName":"` + id + `"}`)
+		require.Equal(t, http.StatusCreated, serve(h, http.MethodPost, BasePath+"/Users", body, testToken).Code)
+	}
+
+	tests := []struct {
+		query   string
+		status  int
+		total   int
+		perPage int
+		first   string
+	}{
+		{query: "", status: http.StatusOK, total: 3, perPage: 3, first: "u1"},
+		{query: "?startIndex=2&count=1", status: http.StatusOK, total: 3, perPage: 1, first: "u2"},
+		{query: "?startIndex=5", status: http.StatusOK, total: 3, perPage: 0},
+		{query: "?count=0", status: http.StatusOK, total: 3, perPage: 0},
+		{query: "?count=many", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := serve(h, http.MethodGet, BasePath+"/Users"+tt.query, nil, testToken)
+			require.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+			var list struct {
+				TotalResults int    `json:"totalResults"`
+				ItemsPerPage int    `json:"itemsPerPage"`
+				Resources    []User `json:"Resources"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+			assert.Equal(t, tt.total, list.TotalResults)
+			assert.Equal(t, tt.perPage, list.ItemsPerPage)
+			require.Len(t, list.Resources, tt.perPage)
+			if tt.first != "" {
+				assert.Equal(t, tt.first, list.Resources[0].ID)
+			}
+		})
+	}
+}
+
+func TestReplaceGroupMembers(t *testing.T) {
+	h := newTestHandler()
+	for _, id := range []string{"u1", "u2", "u3"} {
+		body := []byte(`{"schemas":["` + userSchema + `"],"userName":"` + id + `"}`)
+		require.Equal(t, http.StatusCreated, serve(h, http.MethodPost, BasePath+"/Users", body, testToken).Code)
+	}
+	body := []byte(`{"schemas":["` + groupSchema + `"],"displayName":"team","members":[{"value":"u1"},{"value":"u2"}]}`)
+	require.Equal(t, http.StatusCreated, serve(h, http.MethodPost, BasePath+"/Groups", body, testToken).Code)
+
+	body = []byte(`{"schemas":["` + groupSchema + `"],"displayName":"team","members":[{"value":"u2"},{"value":"u3"}]}`)
+	w := serve(h, http.MethodPut, BasePath+"/Groups/team", body, testToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var group Group
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+	assert.Equal(t, []string{"u2", "u3"}, memberIDs(group.Members))
+
+	// replacing and then adding in one PATCH
+	body = []byte(`{"schemas":["` + patchSchema + `"],"Operations":[
+		{"op":"replace","path":"members","value":[{"value":"u1"}]},
+		{"op":"add","path":"members","value":[{"value":"u3"}]}
+	]}`)
+	w = serve(h, http.MethodPatch, BasePath+"/Groups/team", body, testToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+	assert.Equal(t, []string{"u1", "u3"}, memberIDs(group.Members))
+
+	// removing without a value empties the group
+	body = []byte(`{"schemas":["` + patchSchema + `"],"Operations":[{"op":"remove","path":"members"}]}`)
+	w = serve(h, http.MethodPatch, BasePath+"/Groups/team", body, testToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	group = Group{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+	assert.Empty(t, group.Members)
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    *filter
+		wantErr bool
+	}{
+		{expr: "", want: nil},
+		{expr: `userName eq "alice"`, want: &filter{attribute: "username", value: "alice"}},
+		{expr: `  USERNAME   EQ "a \"quoted\" name" `, want: &filter{attribute: "username", value: `a "quoted" name`}},
+		{expr: `id eq "u1"`, want: &filter{attribute: "id", value: "u1"}},
+		{expr: `userName sw "a"`, wantErr: true},
+		{expr: `userName eq alice`, wantErr: true},
+		{expr: `emails.value eq "a@b.c"`, wantErr: true},
+		{expr: `userName eq "a" and active eq true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseFilter(tt.expr, "userName", "id")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+)
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// User is the SCIM user resource. Attributes the service does not store,
+// such as emails, are accepted and dropped.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Groups      []Ref    `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// username picks the name shown in the service out of the name attributes.
+func (u *User) username() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if full := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); full != "" {
+			return full
+		}
+	}
+	return u.UserName
+}
+
+func toSCIMUser(user *models.User) User {
+	active := user.IsActive
+	resource := User{
+		Schemas:     []string{userSchema},
+		ID:          user.UserId,
+		UserName:    user.UserId,
+		Name:        &Name{Formatted: user.Username},
+		DisplayName: user.Username,
+		Active:      &active,
+		Meta:        &Meta{ResourceType: "User", Location: location("Users", user.UserId)},
+	}
+	if user.TeamName != "" {
+		resource.Groups = []Ref{{
+			Value:   user.TeamName,
+			Display: user.TeamName,
+			Ref:     location("Groups", user.TeamName),
+		}}
+	}
+	return resource
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query().Get("filter"), "userName", "id")
+	if err != nil {
+		h.sendError(w, r, http.StatusBadRequest, "invalidFilter", err)
+		return
+	}
+	users, err := h.dir.ListUsers(r.Context())
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	resources := make([]any, 0, len(users))
+	for _, user := range users {
+		// userName is case insensitive, ids are not
+		if f != nil && (f.attribute == "id" && user.UserId != f.value ||
+			f.attribute == "username" && !strings.EqualFold(user.UserId, f.value)) {
+			continue
+		}
+		resources = append(resources, toSCIMUser(&user))
+	}
+	h.sendList(w, r, resources)
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.dir.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMUser(user))
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var req User
+	if !h.decode(w, r, &req) {
+		return
+	}
+	if req.UserName == "" {
+		h.sendError(w, r, http.StatusBadRequest, "invalidValue", errors.New("userName is required"))
+		return
+	}
+	user := &models.User{
+		UserId:   req.UserName,
+		Username: req.username(),
+		IsActive: req.Active == nil || *req.Active,
+	}
+	created, err := h.dir.CreateUser(r.Context(), user)
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	resource := toSCIMUser(created)
+	w.Header().Set("Location", resource.Meta.Location)
+	h.sendJSON(w, r, http.StatusCreated, resource)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	var req User
+	if !h.decode(w, r, &req) {
+		return
+	}
+	id := r.PathValue("id")
+	if req.UserName != "" && req.UserName != id {
+		h.sendError(w, r, http.StatusBadRequest, "mutability", errors.New("userName can not be changed"))
+		return
+	}
+	user := &models.User{
+		UserId:   id,
+		Username: req.username(),
+		IsActive: req.Active == nil || *req.Active,
+	}
+	if user.Username == "" {
+		user.Username = id
+	}
+	updated, err := h.dir.UpdateUser(r.Context(), user)
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMUser(updated))
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	var req patchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	user, err := h.dir.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	for _, op := range req.Operations {
+		if err := applyUserOperation(user, op); err != nil {
+			h.sendError(w, r, http.StatusBadRequest, "invalidValue", err)
+			return
+		}
+	}
+	updated, err := h.dir.UpdateUser(r.Context(), user)
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	h.sendJSON(w, r, http.StatusOK, toSCIMUser(updated))
+}
+
+// applyUserOperation applies an add or replace of active or of a name
+// attribute. Operations without a path carry the attributes in an object.
+func applyUserOperation(user *models.User, op patchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	default:
+		return fmt.Errorf("unsupported operation %q on a user", op.Op)
+	}
+
+	if op.Path == "" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return fmt.Errorf("value of an operation without path must be an object: %w", err)
+		}
+		for path, value := range attributes {
+			if err := applyUserOperation(user, patchOperation{Op: op.Op, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch strings.ToLower(op.Path) {
+	case "active":
+		active, err := parseBool(op.Value)
+		if err != nil {
+			return err
+		}
+		user.IsActive = active
+	case "displayname", "name.formatted":
+		var name string
+		if err := json.Unmarshal(op.Value, &name); err != nil || name == "" {
+			return fmt.Errorf("%s must be a non empty string", op.Path)
+		}
+		user.Username = name
+	case "username":
+		var userName string
+		if err := json.Unmarshal(op.Value, &userName); err != nil || userName != user.UserId {
+			return errors.New("userName can not be changed")
+		}
+	default:
+		// attributes the service does not store
+	}
+	return nil
+}
+
+// parseBool reads a JSON boolean. Some identity providers send booleans as
+// the strings "True" and "False".
+func parseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("active must be a boolean, got %s", raw)
+}
+
+// deleteUser deactivates the user. Users stay in the service as authors and
+// reviewers of pull requests.
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.dir.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	user.IsActive = false
+	if _, err := h.dir.UpdateUser(r.Context(), user); err != nil {
+		h.sendServiceError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+)
+
+// ListUnavailability returns the unavailability periods of the user that are
+// not over yet, or all of them with includePast.
+func (s *Service) ListUnavailability(ctx context.Context, userID string, includePast bool) (_ []models.Unavailability, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUnavailability")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUnavailability(ctx, userID, includePast)
+}
+
+func (s *Service) CreateUnavailability(ctx context.Context, period *models.Unavailability) (_ *models.Unavailability, err error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateUnavailability")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.repo.GetUser(ctx, period.UserId); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateUnavailability(ctx, period)
+}
+
+func (s *Service) UpdateUnavailability(ctx context.Context, period *models.Unavailability) (_ *models.Unavailability, err error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateUnavailability")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.UpdateUnavailability(ctx, period)
+}
+
+func (s *Service) DeleteUnavailability(ctx context.Context, id int64) (_ *models.Unavailability, err error) {
+	ctx, span := tracer.Start(ctx, "Service.DeleteUnavailability")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.DeleteUnavailability(ctx, id)
+}
+
+// withoutUnavailable drops the users who are in one of their unavailability
+// periods from ids. The is_active flag of those users is left as it is.
+func (s *Service) withoutUnavailable(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	unavailable, err := s.repo.GetUnavailableUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(ids, func(id string) bool {
+		return slices.Contains(unavailable, id)
+	}), nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+)
+
+// bulkCreateBatchSize is how many pull requests a non-atomic bulk create
+// inserts per transaction.
+const bulkCreateBatchSize = 100
+
+// reviewerBalancer hands out the reviewers with the fewest open reviews,
+// counting the ones it handed out itself, so that a batch of pull requests
+// is spread evenly over a team instead of piling up on random members.
+type reviewerBalancer struct {
+	load map[string]int
+}
+
+func newReviewerBalancer(openReviews map[string]int) *reviewerBalancer {
+	return &reviewerBalancer{load: openReviews}
+}
+
+// pick returns up to n of the candidates: the ones with an expertise score
+// first, then the least loaded, then the highest scores, breaking ties at
+// random. scores may be nil.
+func (b *reviewerBalancer) pick(candidates []string, scores map[string]int, n int) []string {
+	shuffled := append([]string{}, candidates...)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	slices.SortStableFunc(shuffled, func(x, y string) int {
+		return cmp.Or(
+			-cmp.Compare(min(scores[x], 1), min(scores[y], 1)),
+			cmp.Compare(b.load[x], b.load[y]),
+			-cmp.Compare(scores[x], scores[y]),
+		)
+	})
+	picked := shuffled[:min(n, len(shuffled))]
+	for _, id := range picked {
+		b.load[id]++
+	}
+	return picked
+}
+
+// PullRequestBulkCreate creates the pull requests and assigns reviewers
+// balanced across the whole batch. results[i] is the outcome of prs[i].
+// Pull requests that can not be created get an error of their own, unless
+// atomic is set: then a single failure leaves all the others skipped.
+func (s *Service) PullRequestBulkCreate(ctx context.Context, prs []*models.PullRequest, atomic bool) (_ []models.BulkCreateResult, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestBulkCreate")
+	defer func() { tracing.End(span, err) }()
+
+	results := make([]models.BulkCreateResult, len(prs))
+	prIDs := make([]string, 0, len(prs))
+	authorIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		prIDs = append(prIDs, pr.PullRequestId)
+		authorIDs = append(authorIDs, pr.AuthorId)
+	}
+
+	existing, err := s.repo.GetPullRequestsByIDs(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(prs))
+	for _, pr := range existing {
+		taken[pr.PullRequestId] = true
+	}
+	authorList, err := s.repo.GetUsersByIDs(ctx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[string]models.User, len(authorList))
+	for _, author := range authorList {
+		authors[author.UserId] = author
+	}
+
+	requiredIDs := make([]string, 0)
+	for _, pr := range prs {
+		for _, id := range pr.RequiredReviewers {
+			if !slices.Contains(requiredIDs, id) {
+				requiredIDs = append(requiredIDs, id)
+			}
+		}
+	}
+	var reviewers map[string]models.User
+	var unavailable []string
+	if len(requiredIDs) > 0 {
+		if reviewers, unavailable, err = s.loadReviewers(ctx, requiredIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// check every pull request before creating any
+	pending := make([]int, 0, len(prs))
+	for i, pr := range prs {
+		if err := s.codeHost.CheckPullRequestID(pr.PullRequestId); err != nil {
+			results[i].Err = fmt.Errorf("%w: %w", models.ErrUnsupportedPRID, err)
+			continue
+		}
+		if taken[pr.PullRequestId] {
+			results[i].Err = models.ErrPRAlreadyExists
+			continue
+		}
+		author, ok := authors[pr.AuthorId]
+		if !ok {
+			results[i].Err = models.ErrAuthorNotFound
+			continue
+		}
+		if author.TeamName == "" {
+			results[i].Err = models.ErrAuthorWithoutTeam
+			continue
+		}
+		if err := checkRequiredReviewers(pr, reviewers, unavailable); err != nil {
+			results[i].Err = err
+			continue
+		}
+		// later duplicates within the batch are taken as well
+		taken[pr.PullRequestId] = true
+		pending = append(pending, i)
+	}
+	if atomic && len(pending) < len(prs) {
+		return results, nil
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	if pending, err = s.assignBalanced(ctx, prs, pending, authors, results); err != nil {
+		return nil, err
+	}
+	if atomic && len(pending) < len(prs) {
+		return results, nil
+	}
+
+	if atomic {
+		batch := make([]*models.PullRequest, 0, len(pending))
+		for _, i := range pending {
+			batch = append(batch, prs[i])
+		}
+		if err := s.repo.CreatePullRequests(ctx, batch); err != nil {
+			return nil, err
+		}
+		for _, i := range pending {
+			results[i].Pr = prs[i]
+		}
+	} else {
+		for start := 0; start < len(pending); start += bulkCreateBatchSize {
+			chunk := pending[start:min(start+bulkCreateBatchSize, len(pending))]
+			if err := s.createBatch(ctx, prs, chunk, results); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, result := range results {
+		if result.Pr == nil {
+			continue
+		}
+		s.metrics.PullRequestCreated(len(result.Pr.AssignedReviewers))
+		if err := s.codeHost.RequestReviewers(ctx, result.Pr.PullRequestId, result.Pr.AssignedReviewers); err != nil {
+			s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", result.Pr.PullRequestId, "error", err.Error())
+		}
+	}
+	return results, nil
+}
+
+// assignBalanced assigns the required reviewers of prs[i] of every i in
+// pending and fills the remaining slots, as many as the reviewer limits of
+// the author's team allow, from the active and available members of that
+// team that are not excluded, code owners and experts of the pull request
+// first. Pull requests that can not get their reviewers get an error in
+// results, the ones that did are returned.
+func (s *Service) assignBalanced(ctx context.Context, prs []*models.PullRequest, pending []int, authors map[string]models.User, results []models.BulkCreateResult) ([]int, error) {
+	teamNames := make([]string, 0)
+	for _, i := range pending {
+		teamName := authors[prs[i].AuthorId].TeamName
+		if !slices.Contains(teamNames, teamName) {
+			teamNames = append(teamNames, teamName)
+		}
+	}
+	members, err := s.repo.GetMembersOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	candidateIDs, err := s.withoutUnavailable(ctx, getActiveUsersIds(members))
+	if err != nil {
+		return nil, err
+	}
+	activeByTeam := make(map[string][]string, len(teamNames))
+	for _, member := range members {
+		if slices.Contains(candidateIDs, member.UserId) {
+			activeByTeam[member.TeamName] = append(activeByTeam[member.TeamName], member.UserId)
+		}
+	}
+	openReviews, err := s.repo.CountOpenReviews(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	var e *experts
+	if slices.ContainsFunc(pending, func(i int) bool { return hasExpertiseHints(prs[i]) }) {
+		// required reviewers may own the files through their tags
+		userIDs := slices.Clone(candidateIDs)
+		for _, i := range pending {
+			userIDs = append(userIDs, prs[i].RequiredReviewers...)
+		}
+		if e, err = s.loadExperts(ctx, teamNames, userIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	settings := make(map[string]*models.TeamSettings, len(teamNames))
+	for _, teamName := range teamNames {
+		if settings[teamName], err = s.reviewerSettings(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+
+	balancer := newReviewerBalancer(openReviews)
+	assigned := make([]int, 0, len(pending))
+	for _, i := range pending {
+		pr := prs[i]
+		teamName := authors[pr.AuthorId].TeamName
+		candidates := candidatesFor(pr, activeByTeam[teamName])
+		if _, maxReviewers := settings[teamName].ReviewerLimits(); len(pr.RequiredReviewers) > maxReviewers {
+			results[i].Err = models.ErrTooManyRequired
+			continue
+		}
+		n := settings[teamName].ReviewerSlots(len(pr.RequiredReviewers)) - len(pr.RequiredReviewers)
+		var picked []string
+		if hasExpertiseHints(pr) {
+			if picked, err = e.pick(balancer, pr, teamName, candidates, n); err != nil {
+				results[i].Err = err
+				continue
+			}
+		} else {
+			picked = balancer.pick(candidates, nil, n)
+		}
+		if len(picked) < n {
+			s.metrics.NoCandidate("bulk_create")
+		}
+		pr.AssignedReviewers = append(slices.Clone(pr.RequiredReviewers), picked...)
+		for _, id := range pr.RequiredReviewers {
+			balancer.load[id]++
+		}
+		assigned = append(assigned, i)
+	}
+	return assigned, nil
+}
+
+// createBatch inserts prs[i] of every i in chunk in one transaction. When the
+// transaction fails because another request took one of the ids meanwhile or
+// kept conflicting with concurrent ones, the pull requests are inserted one
+// by one so that the failure is reported for the right pull request only.
+// Any other failure fails the whole bulk create.
+func (s *Service) createBatch(ctx context.Context, prs []*models.PullRequest, chunk []int, results []models.BulkCreateResult) error {
+	batch := make([]*models.PullRequest, 0, len(chunk))
+	for _, i := range chunk {
+		batch = append(batch, prs[i])
+	}
+	err := s.repo.CreatePullRequests(ctx, batch)
+	if err == nil {
+		for _, i := range chunk {
+			results[i].Pr = prs[i]
+		}
+		return nil
+	}
+	if ctx.Err() != nil || !errors.Is(err, models.ErrPRAlreadyExists) && !errors.Is(err, models.ErrTxConflict) {
+		return err
+	}
+
+	s.log(ctx).Warn("bulk create: batch failed, creating one by one", "size", len(chunk), "error", err.Error())
+	for _, i := range chunk {
+		err := s.repo.CreatePullRequests(ctx, []*models.PullRequest{prs[i]})
+		switch {
+		case err == nil:
+			results[i].Pr = prs[i]
+		case ctx.Err() != nil:
+			return err
+		case errors.Is(err, models.ErrPRAlreadyExists), errors.Is(err, models.ErrTxConflict):
+			results[i].Err = err
+		default:
+			return err
+		}
+	}
+	return nil
+}
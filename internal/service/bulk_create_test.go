@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewerBalancer_SpreadsBatchEvenly(t *testing.T) {
+	balancer := newReviewerBalancer(map[string]int{})
+	candidates := []string{"u1", "u2", "u3", "u4"}
+
+	assigned := map[string]int{}
+	for range 10 {
+		picked := balancer.pick(candidates, nil, 2)
+		require.Len(t, picked, 2)
+		require.NotEqual(t, picked[0], picked[1])
+		for _, id := range picked {
+			assigned[id]++
+		}
+	}
+	// 20 reviews over 4 reviewers
+	for _, id := range candidates {
+		require.Equal(t, 5, assigned[id], id)
+	}
+}
+
+func TestReviewerBalancer_PrefersLeastLoaded(t *testing.T) {
+	balancer := newReviewerBalancer(map[string]int{"u1": 5, "u2": 1})
+
+	require.ElementsMatch(t, []string{"u2", "u3"}, balancer.pick([]string{"u1", "u2", "u3"}, nil, 2))
+	// u2 and u3 have 2 and 1 open reviews now
+	require.Equal(t, []string{"u3"}, balancer.pick([]string{"u1", "u2", "u3"}, nil, 1))
+}
+
+func TestReviewerBalancer_FewCandidates(t *testing.T) {
+	balancer := newReviewerBalancer(map[string]int{})
+
+	require.Equal(t, []string{"u1"}, balancer.pick([]string{"u1"}, nil, 2))
+	picked := balancer.pick(nil, nil, 2)
+	require.NotNil(t, picked)
+	require.Empty(t, picked)
+}
+
+// batchRepo fails every insert of more than one pull request with batchErr
+// and the inserts of the ids in failing with their error.
+type batchRepo struct {
+	Repository
+	batchErr error
+	failing  map[string]error
+}
+
+func (r *batchRepo) CreatePullRequests(ctx context.Context, prs []*models.PullRequest) error {
+	if len(prs) > 1 {
+		return r.batchErr
+	}
+	return r.failing[prs[0].PullRequestId]
+}
+
+func TestCreateBatch(t *testing.T) {
+	prs := []*models.PullRequest{{PullRequestId: "pr-1"}, {PullRequestId: "pr-2"}}
+	dbErr := errors.New("db: error creating prs: connection refused")
+
+	t.Run("conflict falls back to one by one", func(t *testing.T) {
+		s := newTestService(&batchRepo{
+			batchErr: models.ErrPRAlreadyExists,
+			failing:  map[string]error{"pr-2": models.ErrPRAlreadyExists},
+		})
+		results := make([]models.BulkCreateResult, len(prs))
+		require.NoError(t, s.createBatch(context.Background(), prs, []int{0, 1}, results))
+		require.Equal(t, prs[0], results[0].Pr)
+		require.ErrorIs(t, results[1].Err, models.ErrPRAlreadyExists)
+	})
+
+	t.Run("serialization failure falls back to one by one", func(t *testing.T) {
+		s := newTestService(&batchRepo{batchErr: models.ErrTxConflict})
+		results := make([]models.BulkCreateResult, len(prs))
+		require.NoError(t, s.createBatch(context.Background(), prs, []int{0, 1}, results))
+		require.Equal(t, prs[0], results[0].Pr)
+		require.Equal(t, prs[1], results[1].Pr)
+	})
+
+	t.Run("other failure is returned", func(t *testing.T) {
+		s := newTestService(&batchRepo{batchErr: dbErr})
+		results := make([]models.BulkCreateResult, len(prs))
+		require.ErrorIs(t, s.createBatch(context.Background(), prs, []int{0, 1}, results), dbErr)
+		require.Nil(t, results[0].Pr)
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+)
+
+// The methods below serve the sync with the corporate directory. Unlike
+// /team/add they manage users and team membership one change at a time, so
+// a user may be left without a team between changes.
+
+func (s *Service) ListUsers(ctx context.Context) (_ []models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.ListUsers(ctx)
+}
+
+func (s *Service) GetUser(ctx context.Context, userID string) (_ *models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUser")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.GetUser(ctx, userID)
+}
+
+// CreateUser creates a user in no team.
+func (s *Service) CreateUser(ctx context.Context, user *models.User) (_ *models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	user.TeamName = ""
+	created, err := s.repo.InsertUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, models.ErrUserExists
+	}
+	return user, nil
+}
+
+// UpdateUser sets the name and the activity flag of the user, its team is
+// kept.
+func (s *Service) UpdateUser(ctx context.Context, user *models.User) (_ *models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUser(ctx, user.UserId)
+}
+
+// ListTeams returns all teams with their members.
+func (s *Service) ListTeams(ctx context.Context) (_ []models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTeams")
+	defer func() { tracing.End(span, err) }()
+
+	teamNames, err := s.repo.GetTeamNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetMembersOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]models.Team, 0, len(teamNames))
+	index := make(map[string]int, len(teamNames))
+	for i, name := range teamNames {
+		index[name] = i
+		teams = append(teams, models.Team{TeamName: name, Members: []models.TeamMember{}})
+	}
+	for _, member := range members {
+		team := &teams[index[member.TeamName]]
+		team.Members = append(team.Members, toTeamMember(member))
+	}
+	return teams, nil
+}
+
+// CreateTeamWithMembers creates a team of existing users, moving them from
+// their previous teams.
+func (s *Service) CreateTeamWithMembers(ctx context.Context, teamName string, userIDs []string) (_ *models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateTeamWithMembers")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.repo.CreateTeamWithMembers(ctx, teamName, unique(userIDs)); err != nil {
+		return nil, err
+	}
+	return s.GetTeamWithMembers(ctx, teamName)
+}
+
+// UpdateTeamMembers moves the users in add into the team and takes the users
+// in remove out of it. With replace the team consists of the users in add
+// afterwards.
+func (s *Service) UpdateTeamMembers(ctx context.Context, teamName string, add, remove []string, replace bool) (_ *models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateTeamMembers")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.repo.UpdateTeamMembers(ctx, teamName, unique(add), unique(remove), replace); err != nil {
+		return nil, err
+	}
+	return s.GetTeamWithMembers(ctx, teamName)
+}
+
+// DeleteTeam deletes the team, its members stay without a team.
+func (s *Service) DeleteTeam(ctx context.Context, teamName string) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.DeleteTeam")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.DeleteTeam(ctx, teamName)
+}
+
+func toTeamMember(user models.User) models.TeamMember {
+	isActive := user.IsActive
+	return models.TeamMember{
+		UserId:   user.UserId,
+		Username: user.Username,
+		IsActive: &isActive,
+	}
+}
+
+// unique returns the sorted ids without duplicates, never nil.
+func unique(ids []string) []string {
+	ids = append([]string{}, ids...)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
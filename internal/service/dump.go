@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+)
+
+func (s *Service) ExportDump(ctx context.Context) (_ *models.Dump, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ExportDump")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.ExportDump(ctx)
+}
+
+// ImportDump creates or updates everything in d and reports the difference
+// to the current state. With dryRun only the report is made.
+func (s *Service) ImportDump(ctx context.Context, d *models.Dump, dryRun bool) (_ *models.ImportReport, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ImportDump")
+	defer func() { tracing.End(span, err) }()
+
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidInput, err)
+	}
+	current, err := s.repo.ExportDump(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReferences(current, d); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidInput, err)
+	}
+
+	report := diffDumps(current, d)
+	report.DryRun = dryRun
+	if dryRun || !report.Changed() {
+		return report, nil
+	}
+	if err := s.repo.ImportDump(ctx, d); err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("dump imported",
+		"teams_created", len(report.Teams.Created),
+		"users_created", len(report.Users.Created), "users_updated", len(report.Users.Updated),
+		"prs_created", len(report.PullRequests.Created), "prs_updated", len(report.PullRequests.Updated),
+	)
+	return report, nil
+}
+
+// checkReferences makes sure that authors and reviewers of the imported pull
+// requests exist after the import.
+func checkReferences(current, incoming *models.Dump) error {
+	users := make(map[string]bool)
+	for _, d := range []*models.Dump{current, incoming} {
+		for _, team := range d.Teams {
+			for _, member := range team.Members {
+				users[member.UserId] = true
+			}
+		}
+	}
+	for _, pr := range incoming.PullRequests {
+		if !users[pr.AuthorId] {
+			return fmt.Errorf("pull request %q: author %q not found", pr.PullRequestId, pr.AuthorId)
+		}
+		for _, reviewer := range pr.AssignedReviewers {
+			if !users[reviewer] {
+				return fmt.Errorf("pull request %q: reviewer %q not found", pr.PullRequestId, reviewer)
+			}
+		}
+	}
+	return nil
+}
+
+// diffDumps reports what importing incoming changes in current.
+func diffDumps(current, incoming *models.Dump) *models.ImportReport {
+	report := &models.ImportReport{
+		Teams:        newEntityDiff(),
+		Users:        newEntityDiff(),
+		PullRequests: newEntityDiff(),
+	}
+
+	teams := make(map[string]bool, len(current.Teams))
+	type userState struct {
+		member   models.TeamMember
+		teamName string
+	}
+	users := make(map[string]userState)
+	for _, team := range current.Teams {
+		teams[team.TeamName] = true
+		for _, member := range team.Members {
+			users[member.UserId] = userState{member: member, teamName: team.TeamName}
+		}
+	}
+	for _, team := range incoming.Teams {
+		if teams[team.TeamName] {
+			report.Teams.Unchanged++
+		} else {
+			report.Teams.Created = append(report.Teams.Created, team.TeamName)
+		}
+		for _, member := range team.Members {
+			old, ok := users[member.UserId]
+			switch {
+			case !ok:
+				report.Users.Created = append(report.Users.Created, member.UserId)
+			case old.teamName != team.TeamName || old.member.Username != member.Username ||
+				*old.member.IsActive != *member.IsActive:
+				report.Users.Updated = append(report.Users.Updated, member.UserId)
+			default:
+				report.Users.Unchanged++
+			}
+		}
+	}
+
+	prs := make(map[string]models.PullRequest, len(current.PullRequests))
+	for _, pr := range current.PullRequests {
+		prs[pr.PullRequestId] = pr
+	}
+	for _, pr := range incoming.PullRequests {
+		old, ok := prs[pr.PullRequestId]
+		switch {
+		case !ok:
+			report.PullRequests.Created = append(report.PullRequests.Created, pr.PullRequestId)
+		case !samePullRequest(old, pr):
+			report.PullRequests.Updated = append(report.PullRequests.Updated, pr.PullRequestId)
+		default:
+			report.PullRequests.Unchanged++
+		}
+	}
+	return report
+}
+
+func newEntityDiff() models.EntityDiff {
+	return models.EntityDiff{Created: []string{}, Updated: []string{}}
+}
+
+// samePullRequest compares the stored fields of a pull request. A missing
+// creation time in the import keeps the stored one, so it is no change.
+func samePullRequest(old, pr models.PullRequest) bool {
+	if old.PullRequestName != pr.PullRequestName || old.AuthorId != pr.AuthorId || old.Status != pr.Status {
+		return false
+	}
+	if pr.CreatedAt != nil && (old.CreatedAt == nil || *old.CreatedAt != *pr.CreatedAt) {
+		return false
+	}
+	if (old.MergedAt == nil) != (pr.MergedAt == nil) || (old.MergedAt != nil && *old.MergedAt != *pr.MergedAt) {
+		return false
+	}
+	return slices.Equal(sorted(old.AssignedReviewers), sorted(pr.AssignedReviewers))
+}
+
+func sorted(ids []string) []string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	return ids
+}
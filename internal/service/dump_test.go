@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDiffDumps(t *testing.T) {
+	current := &models.Dump{
+		Teams: []models.Team{
+			{TeamName: "backend", Members: []models.TeamMember{
+				{UserId: "u1", Username: "Alice", IsActive: ptr(true)},
+				{UserId: "u2", Username: "Bob", IsActive: ptr(true)},
+				{UserId: "u3", Username: "Carol", IsActive: ptr(true)},
+			}},
+		},
+		PullRequests: []models.PullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "one", AuthorId: "u1", Status: models.StatusOpen,
+				AssignedReviewers: []string{"u2", "u3"}, CreatedAt: ptr("2025-01-02T10:00:00Z")},
+			{PullRequestId: "pr-2", PullRequestName: "two", AuthorId: "u1", Status: models.StatusOpen,
+				AssignedReviewers: []string{"u2"}},
+		},
+	}
+	incoming := &models.Dump{
+		Teams: []models.Team{
+			{TeamName: "backend", Members: []models.TeamMember{
+				{UserId: "u1", Username: "Alice", IsActive: ptr(true)},
+				{UserId: "u2", Username: "Bob", IsActive: ptr(false)},
+			}},
+			{TeamName: "frontend", Members: []models.TeamMember{
+				{UserId: "u3", Username: "Carol", IsActive: ptr(true)},
+				{UserId: "u4", Username: "Dan", IsActive: ptr(true)},
+			}},
+		},
+		PullRequests: []models.PullRequest{
+			// same reviewers in another order, creation time left out
+			{PullRequestId: "pr-1", PullRequestName: "one", AuthorId: "u1", Status: models.StatusOpen,
+				AssignedReviewers: []string{"u3", "u2"}},
+			{PullRequestId: "pr-2", PullRequestName: "two", AuthorId: "u1", Status: models.StatusOpen,
+				AssignedReviewers: []string{"u4"}},
+			{PullRequestId: "pr-3", PullRequestName: "three", AuthorId: "u4", Status: models.StatusOpen},
+		},
+	}
+
+	report := diffDumps(current, incoming)
+	require.Equal(t, models.EntityDiff{Created: []string{"frontend"}, Updated: []string{}, Unchanged: 1}, report.Teams)
+	require.Equal(t, models.EntityDiff{Created: []string{"u4"}, Updated: []string{"u2", "u3"}, Unchanged: 1}, report.Users)
+	require.Equal(t, models.EntityDiff{Created: []string{"pr-3"}, Updated: []string{"pr-2"}, Unchanged: 1}, report.PullRequests)
+	require.True(t, report.Changed())
+
+	require.False(t, diffDumps(current, current).Changed())
+}
+
+func TestCheckReferences(t *testing.T) {
+	current := &models.Dump{
+		Teams: []models.Team{{TeamName: "backend", Members: []models.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: ptr(true)},
+		}}},
+	}
+	incoming := &models.Dump{
+		Teams: []models.Team{{TeamName: "frontend", Members: []models.TeamMember{
+			{UserId: "u2", Username: "Bob", IsActive: ptr(true)},
+		}}},
+		PullRequests: []models.PullRequest{
+			{PullRequestId: "pr-1", AuthorId: "u1", AssignedReviewers: []string{"u2"}},
+		},
+	}
+	require.NoError(t, checkReferences(current, incoming))
+
+	incoming.PullRequests[0].AssignedReviewers = []string{"ghost"}
+	require.ErrorContains(t, checkReferences(current, incoming), "ghost")
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+	"github.com/Sugyk/avito_test_task/pkg/codeowners"
+)
+
+func (s *Service) GetUserExpertise(ctx context.Context, userID string) (_ *models.UserExpertise, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUserExpertise")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	tags, err := s.repo.GetExpertiseOfUsers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	expertise := &models.UserExpertise{UserId: userID, Tags: tags[userID]}
+	if expertise.Tags == nil {
+		expertise.Tags = []string{}
+	}
+	return expertise, nil
+}
+
+// SetUserExpertise replaces the expertise tags of the user.
+func (s *Service) SetUserExpertise(ctx context.Context, expertise *models.UserExpertise) (_ *models.UserExpertise, err error) {
+	ctx, span := tracer.Start(ctx, "Service.SetUserExpertise")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.repo.GetUser(ctx, expertise.UserId); err != nil {
+		return nil, err
+	}
+	expertise.Normalize()
+	if err := s.repo.SetUserExpertise(ctx, expertise.UserId, expertise.Tags); err != nil {
+		return nil, err
+	}
+	return expertise, nil
+}
+
+func (s *Service) GetTeamPathRules(ctx context.Context, teamName string) (_ *models.TeamPathRules, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTeamPathRules")
+	defer func() { tracing.End(span, err) }()
+
+	rules, err := s.repo.GetTeamPathRules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TeamPathRules{TeamName: teamName, Rules: rules}, nil
+}
+
+// SetTeamPathRules replaces the path rules of the team.
+func (s *Service) SetTeamPathRules(ctx context.Context, rules *models.TeamPathRules) (_ *models.TeamPathRules, err error) {
+	ctx, span := tracer.Start(ctx, "Service.SetTeamPathRules")
+	defer func() { tracing.End(span, err) }()
+
+	rules.Normalize()
+	if err := s.repo.SetTeamPathRules(ctx, rules.TeamName, rules.Rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+type compiledPathRule struct {
+	pattern codeowners.Pattern
+	rule    models.PathRule
+}
+
+func compilePathRules(rules []models.PathRule) []compiledPathRule {
+	compiled := make([]compiledPathRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := codeowners.ParsePattern(rule.Pattern)
+		if err != nil {
+			// saved before patterns were checked, it never matches
+			continue
+		}
+		compiled = append(compiled, compiledPathRule{pattern: pattern, rule: rule})
+	}
+	return compiled
+}
+
+// lastMatch returns the last of the rules matching file, nil when none does.
+func lastMatch(rules []compiledPathRule, file string) *models.PathRule {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].pattern.Match(file) {
+			return &rules[i].rule
+		}
+	}
+	return nil
+}
+
+// experts knows the path rules and the CODEOWNERS files of some teams and
+// the expertise of some users, and scores the users against a pull request.
+type experts struct {
+	rules      map[string][]compiledPathRule
+	codeowners map[string][]compiledPathRule
+	tags       map[string][]string
+}
+
+func hasExpertiseHints(pr *models.PullRequest) bool {
+	return len(pr.Files) > 0 || len(pr.Labels) > 0
+}
+
+// loadExperts loads the path rules and the CODEOWNERS files of the teams and
+// the expertise of the users.
+func (s *Service) loadExperts(ctx context.Context, teamNames []string, userIDs []string) (*experts, error) {
+	rules, err := s.repo.GetPathRulesOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.repo.GetCodeownersOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.repo.GetExpertiseOfUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	e := &experts{
+		rules:      make(map[string][]compiledPathRule, len(rules)),
+		codeowners: make(map[string][]compiledPathRule, len(files)),
+		tags:       tags,
+	}
+	for teamName, teamRules := range rules {
+		e.rules[teamName] = compilePathRules(teamRules)
+	}
+	for teamName, content := range files {
+		f, err := codeowners.Parse(strings.NewReader(content))
+		if err != nil {
+			s.log(ctx).Warn("codeowners: can not parse saved file", "team_name", teamName, "error", err.Error())
+			continue
+		}
+		e.codeowners[teamName] = compilePathRules(toTeamCodeowners(teamName, f).Rules)
+	}
+	return e, nil
+}
+
+// owns tells if the user is one of the owners given by rule, directly or
+// through a tag.
+func (e *experts) owns(rule *models.PathRule, userID string) bool {
+	return slices.Contains(rule.Users, userID) || slices.ContainsFunc(rule.Tags, func(tag string) bool {
+		return slices.Contains(e.tags[userID], tag)
+	})
+}
+
+// scores tells how much of the pull request each of the candidates knows:
+// one point for every file the last matching rule of the team gives to the
+// candidate and one for every label among the candidate's tags. Candidates
+// who know nothing of it are left out.
+func (e *experts) scores(pr *models.PullRequest, teamName string, candidates []string) map[string]int {
+	scores := make(map[string]int)
+	for _, file := range pr.Files {
+		rule := lastMatch(e.rules[teamName], file)
+		if rule == nil {
+			continue
+		}
+		for _, candidate := range candidates {
+			if e.owns(rule, candidate) {
+				scores[candidate]++
+			}
+		}
+	}
+	for _, label := range models.NormalizeTags(pr.Labels) {
+		for _, candidate := range candidates {
+			if slices.Contains(e.tags[candidate], label) {
+				scores[candidate]++
+			}
+		}
+	}
+	return scores
+}
+
+// codeOwners returns the candidates owning one of the files of the pull
+// request by the CODEOWNERS file of the team.
+func (e *experts) codeOwners(pr *models.PullRequest, teamName string, candidates []string) []string {
+	owners := []string{}
+	for _, file := range pr.Files {
+		rule := lastMatch(e.codeowners[teamName], file)
+		if rule == nil {
+			continue
+		}
+		for _, candidate := range candidates {
+			if e.owns(rule, candidate) && !slices.Contains(owners, candidate) {
+				owners = append(owners, candidate)
+			}
+		}
+	}
+	return owners
+}
+
+// pick picks up to n reviewers of the pull request among the candidates,
+// members of teamName. When the team's CODEOWNERS file gives some of the
+// files to candidates and none of the required reviewers owns any, one of
+// them is picked first. The others are picked experts first, the least
+// loaded first. When the required reviewers take every slot and leave an
+// available code owner out, it fails with ErrNoSlotForCodeOwner.
+func (e *experts) pick(balancer *reviewerBalancer, pr *models.PullRequest, teamName string, candidates []string, n int) ([]string, error) {
+	scores := e.scores(pr, teamName, candidates)
+	picked := []string{}
+	if len(e.codeOwners(pr, teamName, pr.RequiredReviewers)) == 0 {
+		owners := e.codeOwners(pr, teamName, candidates)
+		if len(owners) > 0 && n <= 0 {
+			return nil, models.ErrNoSlotForCodeOwner
+		}
+		picked = balancer.pick(owners, scores, min(n, 1))
+	}
+	others := slices.DeleteFunc(slices.Clone(candidates), func(id string) bool {
+		return slices.Contains(picked, id)
+	})
+	return append(picked, balancer.pick(others, scores, n-len(picked))...), nil
+}
+
+// pickReviewers picks up to n reviewers of a new pull request among the
+// candidates, members of teamName. Without files and labels they are picked
+// at random, otherwise as experts.pick does.
+func (s *Service) pickReviewers(ctx context.Context, pr *models.PullRequest, teamName string, candidates []string, n int) ([]string, error) {
+	if !hasExpertiseHints(pr) {
+		return getRandomIds(candidates, n), nil
+	}
+	// required reviewers may own the files through their tags
+	e, err := s.loadExperts(ctx, []string{teamName}, append(slices.Clone(candidates), pr.RequiredReviewers...))
+	if err != nil {
+		return nil, err
+	}
+	openReviews, err := s.repo.CountOpenReviews(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return e.pick(newReviewerBalancer(openReviews), pr, teamName, candidates, n)
+}
+
+// SetTeamCodeowners replaces the CODEOWNERS file of the team and returns how
+// it is read.
+func (s *Service) SetTeamCodeowners(ctx context.Context, req *models.TeamCodeownersRequest) (_ *models.TeamCodeowners, err error) {
+	ctx, span := tracer.Start(ctx, "Service.SetTeamCodeowners")
+	defer func() { tracing.End(span, err) }()
+
+	f, err := codeowners.Parse(strings.NewReader(req.Content))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTeamCodeowners(ctx, req.TeamName, req.Content); err != nil {
+		return nil, err
+	}
+	return toTeamCodeowners(req.TeamName, f), nil
+}
+
+// toTeamCodeowners turns the rules of a CODEOWNERS file into path rules, see
+// models.TeamCodeowners.
+func toTeamCodeowners(teamName string, f *codeowners.File) *models.TeamCodeowners {
+	c := &models.TeamCodeowners{
+		TeamName:      teamName,
+		Rules:         make([]models.PathRule, 0, len(f.Rules)),
+		IgnoredOwners: []string{},
+	}
+	for _, rule := range f.Rules {
+		pathRule := models.PathRule{Pattern: rule.Pattern.String(), Users: []string{}, Tags: []string{}}
+		for _, owner := range rule.Owners {
+			switch owner.Kind {
+			case codeowners.User:
+				pathRule.Users = append(pathRule.Users, owner.Name)
+			case codeowners.Team:
+				pathRule.Tags = append(pathRule.Tags, owner.Name)
+			default:
+				if !slices.Contains(c.IgnoredOwners, owner.String()) {
+					c.IgnoredOwners = append(c.IgnoredOwners, owner.String())
+				}
+			}
+		}
+		pathRule.Tags = models.NormalizeTags(pathRule.Tags)
+		c.Rules = append(c.Rules, pathRule)
+	}
+	return c
+}
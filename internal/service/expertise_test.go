@@ -0,0 +1,143 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/pkg/codeowners"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExperts(rules []models.PathRule, tags map[string][]string) *experts {
+	e := &experts{rules: map[string][]compiledPathRule{}, tags: tags}
+	for _, rule := range rules {
+		e.rules["backend"] = append(e.rules["backend"], compiledPathRule{pattern: codeowners.MustParsePattern(rule.Pattern), rule: rule})
+	}
+	return e
+}
+
+func TestExperts_Scores(t *testing.T) {
+	e := newTestExperts([]models.PathRule{
+		{Pattern: "*", Users: []string{"u1"}},
+		{Pattern: "/internal/api/", Tags: []string{"api"}},
+		{Pattern: "/internal/api/openapi/", Users: []string{"u3"}},
+		{Pattern: "/vendor/"},
+	}, map[string][]string{
+		"u2": {"api", "sql"},
+		"u4": {"sql"},
+	})
+	candidates := []string{"u1", "u2", "u3", "u4"}
+
+	pr := &models.PullRequest{Files: []string{
+		"README.md",
+		"internal/api/server.go",
+		"/internal/api/handlers/users.go",
+		"internal/api/openapi/openapi.yaml",
+		"vendor/lib/lib.go",
+	}}
+	require.Equal(t, map[string]int{"u1": 1, "u2": 2, "u3": 1}, e.scores(pr, "backend", candidates))
+
+	pr = &models.PullRequest{Labels: []string{"SQL", "frontend"}}
+	require.Equal(t, map[string]int{"u2": 1, "u4": 1}, e.scores(pr, "backend", candidates))
+
+	// rules of other teams do not apply
+	pr = &models.PullRequest{Files: []string{"README.md"}}
+	require.Empty(t, e.scores(pr, "frontend", candidates))
+}
+
+func TestReviewerBalancer_PrefersExperts(t *testing.T) {
+	balancer := newReviewerBalancer(map[string]int{"u1": 5, "u2": 3, "u3": 0})
+	candidates := []string{"u1", "u2", "u3"}
+
+	// experts first however loaded, the least loaded of them first
+	require.Equal(t, []string{"u2", "u1"}, balancer.pick(candidates, map[string]int{"u1": 3, "u2": 1}, 2))
+	// the other slot goes to the least loaded of the others
+	require.Equal(t, []string{"u1", "u3"}, balancer.pick(candidates, map[string]int{"u1": 1}, 2))
+	// among equally loaded experts the better one
+	balancer = newReviewerBalancer(map[string]int{})
+	require.Equal(t, []string{"u2"}, balancer.pick(candidates, map[string]int{"u1": 1, "u2": 2}, 1))
+}
+
+func TestToTeamCodeowners(t *testing.T) {
+	f, err := codeowners.Parse(strings.NewReader("* @u1 docs@example.com\n/internal/api/ @org/API @u2\n/vendor/\n*.md docs@example.com\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, &models.TeamCodeowners{
+		TeamName: "backend",
+		Rules: []models.PathRule{
+			{Pattern: "*", Users: []string{"u1"}, Tags: []string{}},
+			{Pattern: "/internal/api/", Users: []string{"u2"}, Tags: []string{"api"}},
+			{Pattern: "/vendor/", Users: []string{}, Tags: []string{}},
+			{Pattern: "*.md", Users: []string{}, Tags: []string{}},
+		},
+		IgnoredOwners: []string{"docs@example.com"},
+	}, toTeamCodeowners("backend", f))
+}
+
+func TestExperts_PickCodeOwnerFirst(t *testing.T) {
+	f, err := codeowners.Parse(strings.NewReader("/internal/api/ @org/api\n/vendor/\n"))
+	require.NoError(t, err)
+	e := newTestExperts([]models.PathRule{{Pattern: "*.go", Users: []string{"u2", "u3"}}}, map[string][]string{"u4": {"api"}})
+	e.codeowners = map[string][]compiledPathRule{"backend": compilePathRules(toTeamCodeowners("backend", f).Rules)}
+	candidates := []string{"u1", "u2", "u3", "u4"}
+
+	// u4 owns the file through its tag, however loaded, the other slot goes
+	// to the least loaded expert
+	balancer := newReviewerBalancer(map[string]int{"u2": 3, "u4": 10})
+	pr := &models.PullRequest{Files: []string{"internal/api/server.go"}}
+	require.Equal(t, []string{"u4"}, e.codeOwners(pr, "backend", candidates))
+	picked, err := e.pick(balancer, pr, "backend", candidates, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"u4", "u3"}, picked)
+
+	// files without owners
+	pr = &models.PullRequest{Files: []string{"vendor/lib/lib.go"}}
+	require.Empty(t, e.codeOwners(pr, "backend", candidates))
+	picked, err = e.pick(balancer, pr, "backend", candidates, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3", "u2"}, picked)
+
+	// the owner is not a candidate
+	pr = &models.PullRequest{Files: []string{"internal/api/server.go"}}
+	picked, err = e.pick(balancer, pr, "backend", []string{"u1", "u2"}, 2)
+	require.NoError(t, err)
+	require.Len(t, picked, 2)
+}
+
+func TestExperts_RequiredReviewerOwnsFiles(t *testing.T) {
+	f, err := codeowners.Parse(strings.NewReader("/internal/api/ @org/api @u4\n"))
+	require.NoError(t, err)
+	e := newTestExperts(nil, map[string][]string{"u5": {"api"}})
+	e.codeowners = map[string][]compiledPathRule{"backend": compilePathRules(toTeamCodeowners("backend", f).Rules)}
+	balancer := newReviewerBalancer(map[string]int{"u4": 10})
+
+	// u5 of another team owns the file through its tag, so the slot left
+	// does not have to go to u4
+	pr := &models.PullRequest{Files: []string{"internal/api/server.go"}, RequiredReviewers: []string{"u5"}}
+	picked, err := e.pick(balancer, pr, "backend", []string{"u2", "u4"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2"}, picked)
+
+	// same without a slot left
+	picked, err = e.pick(balancer, pr, "backend", []string{"u2", "u4"}, 0)
+	require.NoError(t, err)
+	require.Empty(t, picked)
+}
+
+func TestExperts_RequiredReviewersLeaveNoSlotForOwner(t *testing.T) {
+	f, err := codeowners.Parse(strings.NewReader("/internal/api/ @u4\n"))
+	require.NoError(t, err)
+	e := newTestExperts(nil, map[string][]string{})
+	e.codeowners = map[string][]compiledPathRule{"backend": compilePathRules(toTeamCodeowners("backend", f).Rules)}
+	balancer := newReviewerBalancer(map[string]int{})
+
+	pr := &models.PullRequest{Files: []string{"internal/api/server.go"}, RequiredReviewers: []string{"u2", "u3"}}
+	_, err = e.pick(balancer, pr, "backend", []string{"u4"}, 0)
+	require.ErrorIs(t, err, models.ErrNoSlotForCodeOwner)
+
+	// without an available owner there is nothing to leave out
+	picked, err := e.pick(balancer, pr, "backend", []string{"u1"}, 0)
+	require.NoError(t, err)
+	require.Empty(t, picked)
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"slices"
 
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
 )
 
 func getActiveUsersIds(teamMembers []models.User) []string {
@@ -20,31 +22,24 @@ func getActiveUsersIds(teamMembers []models.User) []string {
 	return activeMembersIDs
 }
 
-func getTwoRandomIds(ids []string) []string {
-	l := len(ids)
-	result := []string{}
-	if l == 0 {
-		return result
-	}
-	indexes := []int{}
-	indexes = append(indexes, rand.Intn(l))
-	if l > 1 {
-		for {
-			if i := rand.Intn(l); i != indexes[0] {
-				indexes = append(indexes, i)
-				break
-			}
-		}
-	}
-	for _, i := range indexes {
-		result = append(result, ids[i])
-	}
-	return result
+// getRandomIds returns up to n of ids in random order.
+func getRandomIds(ids []string, n int) []string {
+	shuffled := slices.Clone(ids)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:min(n, len(shuffled))]
 }
 
-func (s *Service) PullRequestCreate(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
+func (s *Service) PullRequestCreate(ctx context.Context, pr *models.PullRequest) (_ *models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestCreate")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.codeHost.CheckPullRequestID(pr.PullRequestId); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrUnsupportedPRID, err)
+	}
 	// check PR already exists
-	_, err := s.repo.GetPullRequestBase(ctx, pr.PullRequestId)
+	_, err = s.repo.GetPullRequestBase(ctx, pr.PullRequestId)
 	if err == nil {
 		return nil, models.ErrPRAlreadyExists
 	} else if err != models.ErrPRNotFound {
@@ -58,25 +53,112 @@ func (s *Service) PullRequestCreate(ctx context.Context, pr *models.PullRequest)
 	if err != nil {
 		return nil, fmt.Errorf("db: error checking author: %w", err)
 	}
+	// the directory sync may leave users without a team
+	if author.TeamName == "" {
+		return nil, models.ErrAuthorWithoutTeam
+	}
 
 	teamMembers, err := s.repo.GetTeamMembers(ctx, author.TeamName)
 	if err != nil {
 		return nil, err
 	}
-	activeTeamMembersIds := getActiveUsersIds(teamMembers)
-	activeTeamMembersIds = slices.DeleteFunc(activeTeamMembersIds, func(s string) bool {
-		return s == pr.AuthorId
-	})
-	pr.AssignedReviewers = getTwoRandomIds(activeTeamMembersIds)
+	activeTeamMembersIds, err := s.withoutUnavailable(ctx, getActiveUsersIds(teamMembers))
+	if err != nil {
+		return nil, err
+	}
+	if len(pr.RequiredReviewers) > 0 {
+		users, unavailable, err := s.loadReviewers(ctx, pr.RequiredReviewers)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRequiredReviewers(pr, users, unavailable); err != nil {
+			return nil, err
+		}
+	}
+	settings, err := s.reviewerSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if _, maxReviewers := settings.ReviewerLimits(); len(pr.RequiredReviewers) > maxReviewers {
+		return nil, models.ErrTooManyRequired
+	}
+	n := settings.ReviewerSlots(len(pr.RequiredReviewers)) - len(pr.RequiredReviewers)
+	picked, err := s.pickReviewers(ctx, pr, author.TeamName, candidatesFor(pr, activeTeamMembersIds), n)
+	if err != nil {
+		return nil, err
+	}
+	if len(picked) < n {
+		s.metrics.NoCandidate("create")
+	}
+	pr.AssignedReviewers = append(slices.Clone(pr.RequiredReviewers), picked...)
 	createdPR, err := s.repo.CreatePullRequestAndAssignReviewers(ctx, pr)
 	if err != nil {
 		return nil, err
 	}
+	s.metrics.PullRequestCreated(len(createdPR.AssignedReviewers))
+	if err := s.codeHost.RequestReviewers(ctx, createdPR.PullRequestId, createdPR.AssignedReviewers); err != nil {
+		s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", createdPR.PullRequestId, "error", err.Error())
+	}
 	return createdPR, nil
 }
 
-func (s *Service) PullRequestMerge(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
-	pr, err := s.repo.GetPullRequestBase(ctx, pr.PullRequestId)
+// reviewerSettings returns the settings of teamName whose reviewer limits
+// apply to new pull requests of its members, the default ones for authors
+// without a team.
+func (s *Service) reviewerSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	settings, err := s.repo.GetTeamSettings(ctx, teamName)
+	if errors.Is(err, models.ErrTeamNotFound) {
+		return &models.TeamSettings{TeamName: teamName}, nil
+	}
+	return settings, err
+}
+
+// loadReviewers returns the users among ids, by id, and the ones of them in
+// an unavailability period.
+func (s *Service) loadReviewers(ctx context.Context, ids []string) (map[string]models.User, []string, error) {
+	userList, err := s.repo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	users := make(map[string]models.User, len(userList))
+	for _, user := range userList {
+		users[user.UserId] = user
+	}
+	unavailable, err := s.repo.GetUnavailableUsers(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, unavailable, nil
+}
+
+// checkRequiredReviewers tells why one of the required reviewers of pr can
+// not review it, nil when all of them can.
+func checkRequiredReviewers(pr *models.PullRequest, users map[string]models.User, unavailable []string) error {
+	for _, id := range pr.RequiredReviewers {
+		user, ok := users[id]
+		if !ok {
+			return models.ErrReviewerNotFound
+		}
+		if !user.IsActive || slices.Contains(unavailable, id) {
+			return models.ErrReviewerUnavailable
+		}
+	}
+	return nil
+}
+
+// candidatesFor drops the author and the required and excluded reviewers of
+// pr from candidates, the ones left fill the remaining slots.
+func candidatesFor(pr *models.PullRequest, candidates []string) []string {
+	return slices.DeleteFunc(slices.Clone(candidates), func(id string) bool {
+		return id == pr.AuthorId || slices.Contains(pr.RequiredReviewers, id) || slices.Contains(pr.ExcludedReviewers, id)
+	})
+}
+
+func (s *Service) PullRequestMerge(ctx context.Context, pr *models.PullRequest) (_ *models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestMerge")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err = s.repo.GetPullRequestBase(ctx, pr.PullRequestId)
 	if err == models.ErrPRNotFound {
 		return nil, err
 	}
@@ -92,7 +174,22 @@ func (s *Service) PullRequestMerge(ctx context.Context, pr *models.PullRequest)
 	return mergedPR, nil
 }
 
-func (s *Service) PullRequestReassign(ctx context.Context, prID string, oldUserID string) (*models.PullRequest, string, error) {
+func (s *Service) PullRequestReassign(ctx context.Context, prID string, oldUserID string) (_ *models.PullRequest, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestReassign")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() {
+		if errors.Is(err, models.ErrNoActiveCandidates) {
+			s.metrics.NoCandidate("reassign")
+		}
+	}()
+	return s.reassign(ctx, prID, oldUserID, models.ReassignManual)
+}
+
+// reassign replaces oldUserID among the reviewers of the pull request by an
+// active and available member of the old reviewer's team who was not
+// excluded from it.
+func (s *Service) reassign(ctx context.Context, prID string, oldUserID string, reason models.ReassignReason) (*models.PullRequest, string, error) {
 	var pr = &models.PullRequest{PullRequestId: prID}
 	// check PR exists
 	pr, err := s.repo.GetPullRequestBase(ctx, pr.PullRequestId)
@@ -113,10 +210,17 @@ func (s *Service) PullRequestReassign(ctx context.Context, prID string, oldUserI
 	if err != nil {
 		return nil, "", models.ErrNoActiveCandidates
 	}
-	activeMembersIDs := getActiveUsersIds(teamMembers)
+	activeMembersIDs, err := s.withoutUnavailable(ctx, getActiveUsersIds(teamMembers))
+	if err != nil {
+		return nil, "", err
+	}
 
+	excluded, err := s.repo.GetExcludedReviewers(ctx, prID)
+	if err != nil {
+		return nil, "", err
+	}
 	activeMembersIDs = slices.DeleteFunc(activeMembersIDs, func(s string) bool {
-		return s == pr.AuthorId || s == oldUserID
+		return s == pr.AuthorId || s == oldUserID || slices.Contains(excluded, s)
 	})
 
 	if len(activeMembersIDs) == 0 {
@@ -154,9 +258,145 @@ func (s *Service) PullRequestReassign(ctx context.Context, prID string, oldUserI
 
 	//
 
-	newReviewer, err := s.repo.ReAssignPullRequest(ctx, prID, user, newReviewerID)
+	newReviewer, err := s.repo.ReAssignPullRequest(ctx, prID, user, newReviewerID, reason)
 	if err != nil {
 		return nil, "", err
 	}
+	s.metrics.ReviewerReassigned()
+	if err := s.codeHost.RemoveReviewers(ctx, prID, []string{oldUserID}); err != nil {
+		s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", prID, "error", err.Error())
+	}
+	if err := s.codeHost.RequestReviewers(ctx, prID, []string{newReviewer}); err != nil {
+		s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", prID, "error", err.Error())
+	}
 	return pr, newReviewer, nil
 }
+
+// PullRequestAddReviewer assigns the reviewer to the open pull request. As
+// with reassign, the reviewer must be an active and available member of the
+// author's team other than the author, and must not have been excluded from
+// the pull request at its creation. The pull request may not get more
+// reviewers than the max_reviewers of the author's team.
+func (s *Service) PullRequestAddReviewer(ctx context.Context, prID string, reviewerID string) (_ *models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestAddReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	pr, author, err := s.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	reviewer, err := s.repo.GetUser(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer.UserId == pr.AuthorId || reviewer.TeamName != author.TeamName || !reviewer.IsActive {
+		return nil, models.ErrNotACandidate
+	}
+	available, err := s.withoutUnavailable(ctx, []string{reviewerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(available) == 0 {
+		return nil, models.ErrNotACandidate
+	}
+	excluded, err := s.repo.GetExcludedReviewers(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(excluded, reviewerID) {
+		return nil, models.ErrReviewerExcluded
+	}
+	settings, err := s.reviewerSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	_, maxReviewers := settings.ReviewerLimits()
+
+	pr.AssignedReviewers, err = s.repo.AddReviewer(ctx, prID, reviewerID, maxReviewers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codeHost.RequestReviewers(ctx, prID, []string{reviewerID}); err != nil {
+		s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", prID, "error", err.Error())
+	}
+	return pr, nil
+}
+
+// PullRequestRemoveReviewer unassigns the reviewer from the open pull
+// request. The pull request may not be left with fewer reviewers than the
+// min_reviewers of the author's team.
+func (s *Service) PullRequestRemoveReviewer(ctx context.Context, prID string, reviewerID string) (_ *models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PullRequestRemoveReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	pr, author, err := s.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetUser(ctx, reviewerID); err != nil {
+		return nil, err
+	}
+	settings, err := s.reviewerSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	minReviewers, _ := settings.ReviewerLimits()
+
+	pr.AssignedReviewers, err = s.repo.RemoveReviewer(ctx, prID, reviewerID, minReviewers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codeHost.RemoveReviewers(ctx, prID, []string{reviewerID}); err != nil {
+		s.log(ctx).Warn("code host: can not schedule reviewers sync", "pr_id", prID, "error", err.Error())
+	}
+	return pr, nil
+}
+
+// getOpenPullRequest returns the pull request and its author, failing when
+// the pull request is merged. The repository checks the status again when
+// changing the reviewers.
+func (s *Service) getOpenPullRequest(ctx context.Context, prID string) (*models.PullRequest, *models.User, error) {
+	pr, err := s.repo.GetPullRequestBase(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, nil, models.ErrChangingMergedPR
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, author, nil
+}
+
+func (s *Service) GetPullRequestsByIDs(ctx context.Context, prIDs []string) (_ map[string]models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetPullRequestsByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	prs, err := s.repo.GetPullRequestsByIDs(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.PullRequest, len(prs))
+	for _, pr := range prs {
+		byID[pr.PullRequestId] = pr
+	}
+	return byID, nil
+}
+
+// GetReviewersOfPullRequests returns ids of reviewers assigned to each of the pull requests.
+func (s *Service) GetReviewersOfPullRequests(ctx context.Context, prIDs []string) (_ map[string][]string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetReviewersOfPullRequests")
+	defer func() { tracing.End(span, err) }()
+
+	assignments, err := s.repo.GetReviewersOfPullRequests(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	byPR := make(map[string][]string, len(prIDs))
+	for _, a := range assignments {
+		byPR[a.PullRequestId] = append(byPR[a.PullRequestId], a.UserId)
+	}
+	return byPR, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/Sugyk/avito_test_task/internal/codehost"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo keeps users and pull requests in memory. Methods it does not
+// implement panic through the nil embedded Repository.
+type fakeRepo struct {
+	Repository
+	users    map[string]*models.User
+	prs      map[string]*models.PullRequest
+	settings map[string]*models.TeamSettings
+	excluded map[string][]string
+}
+
+func (r *fakeRepo) GetUser(ctx context.Context, id string) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return &models.User{}, models.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) GetPullRequestBase(ctx context.Context, prID string) (*models.PullRequest, error) {
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, models.ErrPRNotFound
+	}
+	return &models.PullRequest{PullRequestId: pr.PullRequestId, AuthorId: pr.AuthorId, Status: pr.Status}, nil
+}
+
+func (r *fakeRepo) GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error) {
+	prs := []models.PullRequest{}
+	for _, id := range prIDs {
+		if pr, ok := r.prs[id]; ok {
+			prs = append(prs, *pr)
+		}
+	}
+	return prs, nil
+}
+
+func (r *fakeRepo) GetUsersByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	users := []models.User{}
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeRepo) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	settings, ok := r.settings[teamName]
+	if !ok {
+		return nil, models.ErrTeamNotFound
+	}
+	return settings, nil
+}
+
+func (r *fakeRepo) GetExcludedReviewers(ctx context.Context, prID string) ([]string, error) {
+	return r.excluded[prID], nil
+}
+
+func (r *fakeRepo) GetUnavailableUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	return []string{}, nil
+}
+
+func (r *fakeRepo) AddReviewer(ctx context.Context, prID string, reviewerID string, maxReviewers int) ([]string, error) {
+	pr := r.prs[prID]
+	if len(pr.AssignedReviewers) >= maxReviewers {
+		return nil, models.ErrTooManyReviewers
+	}
+	pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+	return pr.AssignedReviewers, nil
+}
+
+func (r *fakeRepo) RemoveReviewer(ctx context.Context, prID string, reviewerID string, minReviewers int) ([]string, error) {
+	pr := r.prs[prID]
+	if !slices.Contains(pr.AssignedReviewers, reviewerID) {
+		return nil, models.ErrUserNotAssignedToPR
+	}
+	if len(pr.AssignedReviewers) <= minReviewers {
+		return nil, models.ErrTooFewReviewers
+	}
+	pr.AssignedReviewers = slices.DeleteFunc(pr.AssignedReviewers, func(id string) bool { return id == reviewerID })
+	return pr.AssignedReviewers, nil
+}
+
+func (r *fakeRepo) GetTeamMembers(ctx context.Context, teamName string) ([]models.User, error) {
+	members := []models.User{}
+	for _, user := range r.users {
+		if user.TeamName == teamName {
+			members = append(members, *user)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeRepo) CreatePullRequestAndAssignReviewers(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
+	pr.Status = models.StatusOpen
+	r.prs[pr.PullRequestId] = pr
+	return pr, nil
+}
+
+// fakeMetrics records the operations that found too few candidates.
+type fakeMetrics struct {
+	noCandidate []string
+}
+
+func (m *fakeMetrics) PullRequestCreated(reviewers int) {}
+func (m *fakeMetrics) ReviewerReassigned()              {}
+func (m *fakeMetrics) NoCandidate(operation string) {
+	m.noCandidate = append(m.noCandidate, operation)
+}
+
+func newTestService(repo Repository) *Service {
+	return NewService(repo, codehost.NopClient{}, &fakeMetrics{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestPullRequestRemoveReviewer_AuthorWithoutTeam(t *testing.T) {
+	repo := &fakeRepo{
+		users: map[string]*models.User{
+			"u1": {UserId: "u1", IsActive: true},
+			"u2": {UserId: "u2", TeamName: "backend", IsActive: true},
+		},
+		prs: map[string]*models.PullRequest{
+			"pr-1": {PullRequestId: "pr-1", AuthorId: "u1", Status: models.StatusOpen, AssignedReviewers: []string{"u2"}},
+		},
+	}
+	s := newTestService(repo)
+
+	// without a team the default limits apply, and nobody is a candidate
+	pr, err := s.PullRequestRemoveReviewer(context.Background(), "pr-1", "u2")
+	require.NoError(t, err)
+	require.Empty(t, pr.AssignedReviewers)
+
+	_, err = s.PullRequestAddReviewer(context.Background(), "pr-1", "u2")
+	require.ErrorIs(t, err, models.ErrNotACandidate)
+}
+
+func TestCheckRequiredReviewers(t *testing.T) {
+	users := map[string]models.User{
+		"u2": {UserId: "u2", IsActive: true},
+		"u3": {UserId: "u3", IsActive: false},
+		"u4": {UserId: "u4", IsActive: true},
+	}
+	unavailable := []string{"u4"}
+
+	tests := []struct {
+		name     string
+		required []string
+		wantErr  error
+	}{
+		{name: "none", required: nil},
+		{name: "active", required: []string{"u2"}},
+		{name: "unknown", required: []string{"u2", "ghost"}, wantErr: models.ErrReviewerNotFound},
+		{name: "inactive", required: []string{"u3"}, wantErr: models.ErrReviewerUnavailable},
+		{name: "unavailable", required: []string{"u4"}, wantErr: models.ErrReviewerUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &models.PullRequest{AuthorId: "u1", RequiredReviewers: tt.required}
+			require.ErrorIs(t, checkRequiredReviewers(pr, users, unavailable), tt.wantErr)
+		})
+	}
+}
+
+func TestCandidatesFor(t *testing.T) {
+	pr := &models.PullRequest{AuthorId: "u1", RequiredReviewers: []string{"u2"}, ExcludedReviewers: []string{"u4"}}
+	candidates := []string{"u1", "u2", "u3", "u4", "u5"}
+
+	require.Equal(t, []string{"u3", "u5"}, candidatesFor(pr, candidates))
+	require.Equal(t, []string{"u1", "u2", "u3", "u4", "u5"}, candidates)
+}
+
+func TestPullRequestCreate_UnsupportedID(t *testing.T) {
+	s := NewService(&fakeRepo{}, codehost.NewGitHubClient("http://unused", "", "acme/api"), nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := s.PullRequestCreate(context.Background(), &models.PullRequest{PullRequestId: "pr-1001", AuthorId: "u1"})
+	require.ErrorIs(t, err, models.ErrUnsupportedPRID)
+
+	results, err := s.PullRequestBulkCreate(context.Background(), []*models.PullRequest{{PullRequestId: "pr-1001", AuthorId: "u1"}}, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0].Err, models.ErrUnsupportedPRID)
+}
+
+func TestPullRequestCreate_RequiredReviewersWithinTeamLimit(t *testing.T) {
+	repo := &fakeRepo{
+		users: map[string]*models.User{
+			"u1": {UserId: "u1", TeamName: "backend", IsActive: true},
+			"u2": {UserId: "u2", TeamName: "backend", IsActive: true},
+			"u3": {UserId: "u3", TeamName: "backend", IsActive: true},
+			"u4": {UserId: "u4", TeamName: "backend", IsActive: true},
+		},
+		prs:      map[string]*models.PullRequest{},
+		settings: map[string]*models.TeamSettings{"backend": {TeamName: "backend", MaxReviewers: ptr(3)}},
+	}
+	s := newTestService(repo)
+	required := []string{"u2", "u3", "u4"}
+
+	pr, err := s.PullRequestCreate(context.Background(), &models.PullRequest{PullRequestId: "pr-1", AuthorId: "u1", RequiredReviewers: required})
+	require.NoError(t, err)
+	require.Equal(t, required, pr.AssignedReviewers)
+
+	repo.settings["backend"].MaxReviewers = nil
+	_, err = s.PullRequestCreate(context.Background(), &models.PullRequest{PullRequestId: "pr-2", AuthorId: "u1", RequiredReviewers: required})
+	require.ErrorIs(t, err, models.ErrTooManyRequired)
+}
+
+func TestPullRequestCreate_TooFewCandidates(t *testing.T) {
+	repo := &fakeRepo{
+		users: map[string]*models.User{
+			"u1": {UserId: "u1", TeamName: "backend", IsActive: true},
+			"u2": {UserId: "u2", TeamName: "backend", IsActive: true},
+		},
+		prs:      map[string]*models.PullRequest{},
+		settings: map[string]*models.TeamSettings{},
+	}
+	s := newTestService(repo)
+
+	pr, err := s.PullRequestCreate(context.Background(), &models.PullRequest{PullRequestId: "pr-1", AuthorId: "u1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+	require.Equal(t, []string{"create"}, s.metrics.(*fakeMetrics).noCandidate)
+}
+
+func TestPullRequestCreate_AuthorWithoutTeam(t *testing.T) {
+	repo := &fakeRepo{
+		users: map[string]*models.User{"u1": {UserId: "u1", IsActive: true}},
+		prs:   map[string]*models.PullRequest{},
+	}
+	s := newTestService(repo)
+
+	_, err := s.PullRequestCreate(context.Background(), &models.PullRequest{PullRequestId: "pr-1", AuthorId: "u1"})
+	require.ErrorIs(t, err, models.ErrAuthorWithoutTeam)
+
+	results, err := s.PullRequestBulkCreate(context.Background(), []*models.PullRequest{{PullRequestId: "pr-1", AuthorId: "u1"}}, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0].Err, models.ErrAuthorWithoutTeam)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"log/slog"
 
+	"go.opentelemetry.io/otel"
+
+	"github.com/Sugyk/avito_test_task/internal/logging"
 	"github.com/Sugyk/avito_test_task/internal/models"
 )
 
@@ -12,24 +15,82 @@ type Repository interface {
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
 	UsersSetIsActive(ctx context.Context, userID string, isActive bool) error
 	CreatePullRequestAndAssignReviewers(ctx context.Context, pullRequest *models.PullRequest) (*models.PullRequest, error)
+	CreatePullRequests(ctx context.Context, prs []*models.PullRequest) error
 	MergePullRequest(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error)
-	ReAssignPullRequest(ctx context.Context, prID string, oldUser *models.User, newReviewerId string) (string, error)
+	ReAssignPullRequest(ctx context.Context, prID string, oldUser *models.User, newReviewerId string, reason models.ReassignReason) (string, error)
+	GetExcludedReviewers(ctx context.Context, prID string) ([]string, error)
+	AddReviewer(ctx context.Context, prID string, reviewerID string, maxReviewers int) ([]string, error)
+	RemoveReviewer(ctx context.Context, prID string, reviewerID string, minReviewers int) ([]string, error)
 	GetUsersReview(ctx context.Context, userID string) ([]models.PullRequestShort, error)
 	GetPullRequestBase(ctx context.Context, prID string) (*models.PullRequest, error)
 	GetUser(ctx context.Context, id string) (*models.User, error)
 	GetTeamMembers(ctx context.Context, team_name string) ([]models.User, error)
 	GetTeamBase(ctx context.Context, team *models.Team) (*models.Team, error)
 	GetPRReviewers(ctx context.Context, prID string) ([]string, error)
+	GetUsersByIDs(ctx context.Context, ids []string) ([]models.User, error)
+	GetMembersOfTeams(ctx context.Context, teamNames []string) ([]models.User, error)
+	GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error)
+	GetReviewersOfPullRequests(ctx context.Context, prIDs []string) ([]models.ReviewAssignment, error)
+	GetReviewsOfUsers(ctx context.Context, userIDs []string) ([]models.ReviewAssignment, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	ExportDump(ctx context.Context) (*models.Dump, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+	InsertUser(ctx context.Context, user *models.User) (bool, error)
+	UpdateUser(ctx context.Context, user *models.User) error
+	GetTeamNames(ctx context.Context) ([]string, error)
+	CreateTeamWithMembers(ctx context.Context, teamName string, userIDs []string) error
+	UpdateTeamMembers(ctx context.Context, teamName string, add, remove []string, replace bool) error
+	DeleteTeam(ctx context.Context, teamName string) error
+	ImportDump(ctx context.Context, d *models.Dump) error
+	ListUnavailability(ctx context.Context, userID string, includePast bool) ([]models.Unavailability, error)
+	CreateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error)
+	UpdateUnavailability(ctx context.Context, period *models.Unavailability) (*models.Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id int64) (*models.Unavailability, error)
+	GetUnavailableUsers(ctx context.Context, userIDs []string) ([]string, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error)
+	GetStaleReviews(ctx context.Context) ([]models.ReviewAssignment, error)
+	GetOverdueReviews(ctx context.Context, teamName string) ([]models.OverdueReview, error)
+	GetExpertiseOfUsers(ctx context.Context, userIDs []string) (map[string][]string, error)
+	SetUserExpertise(ctx context.Context, userID string, tags []string) error
+	GetTeamPathRules(ctx context.Context, teamName string) ([]models.PathRule, error)
+	GetPathRulesOfTeams(ctx context.Context, teamNames []string) (map[string][]models.PathRule, error)
+	SetTeamPathRules(ctx context.Context, teamName string, rules []models.PathRule) error
+	GetCodeownersOfTeams(ctx context.Context, teamNames []string) (map[string]string, error)
+	SetTeamCodeowners(ctx context.Context, teamName string, content string) error
+}
+
+type CodeHost interface {
+	CheckPullRequestID(prID string) error
+	RequestReviewers(ctx context.Context, prID string, reviewers []string) error
+	RemoveReviewers(ctx context.Context, prID string, reviewers []string) error
+}
+
+type Metrics interface {
+	PullRequestCreated(reviewers int)
+	ReviewerReassigned()
+	NoCandidate(operation string)
 }
 
 type Service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo     Repository
+	codeHost CodeHost
+	metrics  Metrics
+	logger   *slog.Logger
 }
 
-func NewService(repo Repository, logger *slog.Logger) *Service {
+func NewService(repo Repository, codeHost CodeHost, metrics Metrics, logger *slog.Logger) *Service {
 	return &Service{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		codeHost: codeHost,
+		metrics:  metrics,
+		logger:   logger,
 	}
 }
+
+// log returns the request scoped logger, falling back to the service one.
+func (s *Service) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+var tracer = otel.Tracer("github.com/Sugyk/avito_test_task/internal/service")
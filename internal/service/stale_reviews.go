@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
+)
+
+// ReassignStaleReviews hands the reviews that stayed with their reviewer
+// longer than the team's stale_review_hours over to other members, the way
+// PullRequestReassign does, and returns how many were reassigned. Reviews
+// without another candidate stay with their reviewer until the next run.
+func (s *Service) ReassignStaleReviews(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ReassignStaleReviews")
+	defer func() { tracing.End(span, err) }()
+
+	reviews, err := s.repo.GetStaleReviews(ctx)
+	if err != nil {
+		return 0, err
+	}
+	reassigned := 0
+	for _, review := range reviews {
+		_, newReviewer, err := s.reassign(ctx, review.PullRequestId, review.UserId, models.ReassignStale)
+		switch {
+		case err == nil:
+			reassigned++
+			s.log(ctx).Info("stale review reassigned",
+				"pr_id", review.PullRequestId, "old_reviewer_id", review.UserId, "new_reviewer_id", newReviewer)
+		case ctx.Err() != nil:
+			return reassigned, err
+		case errors.Is(err, models.ErrNoActiveCandidates):
+			s.metrics.NoCandidate("stale_review")
+			s.log(ctx).Debug("stale review: no candidate", "pr_id", review.PullRequestId, "reviewer_id", review.UserId)
+		default:
+			s.log(ctx).Warn("stale review: reassignment failed",
+				"pr_id", review.PullRequestId, "reviewer_id", review.UserId, "error", err.Error())
+		}
+	}
+	return reassigned, nil
+}
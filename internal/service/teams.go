@@ -4,10 +4,14 @@ import (
 	"context"
 
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
 )
 
-func (s *Service) CreateOrUpdateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
-	team, err := s.repo.GetTeamBase(ctx, team)
+func (s *Service) CreateOrUpdateTeam(ctx context.Context, team *models.Team) (_ *models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateOrUpdateTeam")
+	defer func() { tracing.End(span, err) }()
+
+	team, err = s.repo.GetTeamBase(ctx, team)
 	if err == nil {
 		return nil, models.ErrTeamExists
 	}
@@ -22,7 +26,10 @@ func (s *Service) CreateOrUpdateTeam(ctx context.Context, team *models.Team) (*m
 	return team, nil
 }
 
-func (s *Service) GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error) {
+func (s *Service) GetTeamWithMembers(ctx context.Context, teamName string) (_ *models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTeamWithMembers")
+	defer func() { tracing.End(span, err) }()
+
 	team, err := s.repo.GetTeam(ctx, teamName)
 	if err != nil {
 		return nil, err
@@ -32,3 +39,34 @@ func (s *Service) GetTeamWithMembers(ctx context.Context, teamName string) (*mod
 	}
 	return team, nil
 }
+
+func (s *Service) GetTeamSettings(ctx context.Context, teamName string) (_ *models.TeamSettings, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTeamSettings")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.GetTeamSettings(ctx, teamName)
+}
+
+// SetTeamSettings replaces all settings of the team, the ones left out are
+// reset to their defaults.
+func (s *Service) SetTeamSettings(ctx context.Context, settings *models.TeamSettings) (_ *models.TeamSettings, err error) {
+	ctx, span := tracer.Start(ctx, "Service.SetTeamSettings")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.UpdateTeamSettings(ctx, settings)
+}
+
+// ReviewsOverdue returns the reviews past the SLA of their reviewer's team,
+// of all teams when teamName is empty.
+func (s *Service) ReviewsOverdue(ctx context.Context, teamName string) (_ []models.OverdueReview, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ReviewsOverdue")
+	defer func() { tracing.End(span, err) }()
+
+	if teamName != "" {
+		// check team exists
+		if _, err := s.repo.GetTeamSettings(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetOverdueReviews(ctx, teamName)
+}
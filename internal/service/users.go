@@ -4,9 +4,13 @@ import (
 	"context"
 
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/tracing"
 )
 
-func (s *Service) UsersSetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+func (s *Service) UsersSetIsActive(ctx context.Context, userID string, isActive bool) (_ *models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.UsersSetIsActive")
+	defer func() { tracing.End(span, err) }()
+
 	user, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -20,10 +24,59 @@ func (s *Service) UsersSetIsActive(ctx context.Context, userID string, isActive
 	return user, nil
 }
 
-func (s *Service) UsersGetReview(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+func (s *Service) UsersGetReview(ctx context.Context, userID string) (_ []models.PullRequestShort, err error) {
+	ctx, span := tracer.Start(ctx, "Service.UsersGetReview")
+	defer func() { tracing.End(span, err) }()
+
 	userPRs, err := s.repo.GetUsersReview(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	return userPRs, nil
 }
+
+func (s *Service) GetUsersByIDs(ctx context.Context, ids []string) (_ map[string]models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUsersByIDs")
+	defer func() { tracing.End(span, err) }()
+
+	users, err := s.repo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.User, len(users))
+	for _, user := range users {
+		byID[user.UserId] = user
+	}
+	return byID, nil
+}
+
+func (s *Service) GetMembersOfTeams(ctx context.Context, teamNames []string) (_ map[string][]models.User, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetMembersOfTeams")
+	defer func() { tracing.End(span, err) }()
+
+	members, err := s.repo.GetMembersOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	byTeam := make(map[string][]models.User, len(teamNames))
+	for _, member := range members {
+		byTeam[member.TeamName] = append(byTeam[member.TeamName], member)
+	}
+	return byTeam, nil
+}
+
+// GetReviewsOfUsers returns ids of pull requests assigned to each of the users.
+func (s *Service) GetReviewsOfUsers(ctx context.Context, userIDs []string) (_ map[string][]string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetReviewsOfUsers")
+	defer func() { tracing.End(span, err) }()
+
+	assignments, err := s.repo.GetReviewsOfUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string][]string, len(userIDs))
+	for _, a := range assignments {
+		byUser[a.UserId] = append(byUser[a.UserId], a.PullRequestId)
+	}
+	return byUser, nil
+}
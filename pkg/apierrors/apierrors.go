@@ -0,0 +1,107 @@
+// Package apierrors holds the error codes returned by the service API and
+// the sentinel errors they stand for, so that clients outside this module can
+// match on them with errors.Is.
+package apierrors
+
+import "errors"
+
+const (
+	CodeTeamExists   = "TEAM_EXISTS"
+	CodePrExists     = "PR_EXISTS"
+	CodePrMerged     = "PR_MERGED"
+	CodeNotAssigned  = "NOT_ASSIGNED"
+	CodeNoCandidate  = "NO_CANDIDATE"
+	CodeNotFound     = "NOT_FOUND"
+	CodeInvalidInput = "INVALID_INPUT"
+	CodeInternal     = "INTERNAL_ERROR"
+	CodeRateLimited  = "RATE_LIMITED"
+	CodeUnauthorized = "UNAUTHORIZED"
+
+	CodeAlreadyAssigned = "ALREADY_ASSIGNED"
+	CodeReviewerLimit   = "REVIEWER_LIMIT"
+
+	CodeIdempotencyConflict = "IDEMPOTENCY_CONFLICT"
+)
+
+var (
+	ErrTeamExists          = errors.New("team_name already exists")
+	ErrInternalError       = errors.New("internal server error")
+	ErrTeamNotFound        = errors.New("team_name not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrPRAlreadyExists     = errors.New("PR id already exists")
+	ErrUnsupportedPRID     = errors.New("PR id does not name a pull request of the code host")
+	ErrAuthorNotFound      = errors.New("author not found")
+	ErrAuthorWithoutTeam   = errors.New("author is not a member of any team")
+	ErrPRNotFound          = errors.New("PR not found")
+	ErrReassigningMergedPR = errors.New("cannot reassign on merged PR")
+	ErrUserNotAssignedToPR = errors.New("reviewer is not assigned to this PR")
+	ErrNoActiveCandidates  = errors.New("no active replacement candidate in team")
+	ErrNoReviewers         = errors.New("no reviewers assigned to PR")
+	ErrPeriodNotFound      = errors.New("unavailability period not found")
+	ErrReviewerNotFound    = errors.New("required reviewer not found")
+	ErrReviewerUnavailable = errors.New("required reviewer is inactive or unavailable")
+	ErrTooManyRequired     = errors.New("required reviewers exceed the maximum number of reviewers of the team")
+	ErrNoSlotForCodeOwner  = errors.New("required reviewers take every slot but none of them owns the changed files")
+	ErrChangingMergedPR    = errors.New("cannot change reviewers of merged PR")
+	ErrUserAlreadyAssigned = errors.New("reviewer is already assigned to this PR")
+	ErrNotACandidate       = errors.New("reviewer is not an active and available member of the author's team")
+	ErrReviewerExcluded    = errors.New("reviewer is excluded from this PR")
+	ErrTooManyReviewers    = errors.New("PR already has the maximum number of reviewers of the team")
+	ErrTooFewReviewers     = errors.New("PR already has the minimum number of reviewers of the team")
+	ErrRateLimited         = errors.New("rate limit exceeded")
+	ErrBodyTooLarge        = errors.New("request body too large")
+	ErrUnauthorized        = errors.New("missing or invalid bearer token")
+
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+
+	// ErrNotFound, ErrInvalidInput, ErrPRMerged, ErrNoCandidate,
+	// ErrReviewerLimit and ErrIdempotencyConflict stand for codes shared by
+	// several of the errors above, clients get them instead.
+	ErrNotFound            = errors.New("not found")
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrPRMerged            = errors.New("PR is merged")
+	ErrNoCandidate         = errors.New("no suitable reviewer")
+	ErrReviewerLimit       = errors.New("reviewer limit of the team reached")
+	ErrIdempotencyConflict = errors.New("idempotency key conflict")
+)
+
+// sentinelByCode is the error each code stands for on the client side.
+var sentinelByCode = map[string]error{
+	CodeTeamExists:   ErrTeamExists,
+	CodePrExists:     ErrPRAlreadyExists,
+	CodePrMerged:     ErrPRMerged,
+	CodeNotAssigned:  ErrUserNotAssignedToPR,
+	CodeNoCandidate:  ErrNoCandidate,
+	CodeNotFound:     ErrNotFound,
+	CodeInvalidInput: ErrInvalidInput,
+	CodeInternal:     ErrInternalError,
+	CodeRateLimited:  ErrRateLimited,
+	CodeUnauthorized: ErrUnauthorized,
+
+	CodeAlreadyAssigned: ErrUserAlreadyAssigned,
+	CodeReviewerLimit:   ErrReviewerLimit,
+
+	CodeIdempotencyConflict: ErrIdempotencyConflict,
+}
+
+// Error is returned by FromCode for codes this package does not know, e.g.
+// ones added by a newer version of the service.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// FromCode maps an error code from an error response to its sentinel error.
+// Codes shared by several errors map to the generic sentinel of the code,
+// e.g. every NOT_FOUND to ErrNotFound. Unknown codes give an *Error.
+func FromCode(code, message string) error {
+	if sentinel, ok := sentinelByCode[code]; ok {
+		return sentinel
+	}
+	return &Error{Code: code, Message: message}
+}
@@ -0,0 +1,295 @@
+// Package apitypes holds the request and response bodies of the service API,
+// so that clients outside this module can build and read them.
+package apitypes
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type TeamMember struct {
+	UserId   string `json:"user_id" db:"id"`
+	Username string `json:"username" db:"name"`
+	IsActive *bool  `json:"is_active" db:"isactive"`
+}
+
+func (t *TeamMember) Validate() error {
+	if t.UserId == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if t.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if t.IsActive == nil {
+		return fmt.Errorf("is_active is required")
+	}
+	return nil
+}
+
+type Team struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+}
+
+func (t *Team) Validate() error {
+	if t.TeamName == "" {
+		return fmt.Errorf("team_name is required")
+	}
+	if len(t.Members) == 0 {
+		return fmt.Errorf("team must contain at least one member")
+	}
+	for i, member := range t.Members {
+		if err := member.Validate(); err != nil {
+			return fmt.Errorf("invalid team member %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+type User struct {
+	UserId   string `json:"user_id" db:"id"`
+	Username string `json:"username" db:"name"`
+	TeamName string `json:"team_name" db:"team_name"`
+	IsActive bool   `json:"is_active" db:"isactive"`
+}
+
+type TeamAddResponse201 struct {
+	Team Team `json:"team"`
+}
+
+type UsersSetIsActiveRequest struct {
+	UserId   string `json:"user_id"`
+	IsActive *bool  `json:"is_active"`
+}
+
+func (u *UsersSetIsActiveRequest) Validate() error {
+	if u.UserId == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if u.IsActive == nil {
+		return fmt.Errorf("is_active is required")
+	}
+	return nil
+}
+
+type UsersSerIsActiveResponse200 struct {
+	User User `json:"user"`
+}
+
+type PullRequest struct {
+	PullRequestId     string   `json:"pull_request_id" db:"id"`
+	PullRequestName   string   `json:"pull_request_name" db:"title"`
+	AuthorId          string   `json:"author_id" db:"author_id"`
+	Status            Status   `json:"status" db:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	CreatedAt         *string  `json:"createdAt,omitempty" db:"created_at"`
+	MergedAt          *string  `json:"mergedAt,omitempty" db:"merged_at"`
+	// Files, Labels, RequiredReviewers and ExcludedReviewers only serve to
+	// pick the reviewers, they are not stored.
+	Files             []string `json:"-" db:"-"`
+	Labels            []string `json:"-" db:"-"`
+	RequiredReviewers []string `json:"-" db:"-"`
+	ExcludedReviewers []string `json:"-" db:"-"`
+}
+
+// ReviewersPerPR is how many reviewers a new pull request gets.
+const ReviewersPerPR = 2
+
+type PullRequestCreateRequest struct {
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorId        string `json:"author_id"`
+	// Files are the paths changed by the pull request and Labels its labels.
+	// Reviewers who are experts in them are preferred.
+	Files  []string `json:"files,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	// RequiredReviewers are assigned whatever the selection, the remaining
+	// slots are filled from the team without ExcludedReviewers.
+	RequiredReviewers []string `json:"required_reviewers,omitempty"`
+	ExcludedReviewers []string `json:"excluded_reviewers,omitempty"`
+}
+
+func (p *PullRequestCreateRequest) Validate() error {
+	if p.PullRequestId == "" {
+		return fmt.Errorf("pull_request_id is required")
+	}
+	if p.PullRequestName == "" {
+		return fmt.Errorf("pull_request_name is required")
+	}
+	if p.AuthorId == "" {
+		return fmt.Errorf("author_id is required")
+	}
+	if slices.Contains(p.Files, "") {
+		return fmt.Errorf("files must not be empty")
+	}
+	if slices.Contains(p.Labels, "") {
+		return fmt.Errorf("labels must not be empty")
+	}
+	if err := validateReviewerIds("required_reviewers", p.RequiredReviewers); err != nil {
+		return err
+	}
+	if err := validateReviewerIds("excluded_reviewers", p.ExcludedReviewers); err != nil {
+		return err
+	}
+	if slices.Contains(p.RequiredReviewers, p.AuthorId) {
+		return fmt.Errorf("required_reviewers must not contain the author")
+	}
+	for _, id := range p.RequiredReviewers {
+		if slices.Contains(p.ExcludedReviewers, id) {
+			return fmt.Errorf("reviewer %s is both required and excluded", id)
+		}
+	}
+	return nil
+}
+
+func validateReviewerIds(field string, ids []string) error {
+	for i, id := range ids {
+		if id == "" {
+			return fmt.Errorf("%s must not be empty", field)
+		}
+		if slices.Contains(ids[:i], id) {
+			return fmt.Errorf("%s must not contain duplicates", field)
+		}
+	}
+	return nil
+}
+
+func (p *PullRequestCreateRequest) ToPullRequest() *PullRequest {
+	return &PullRequest{
+		PullRequestId:     p.PullRequestId,
+		PullRequestName:   p.PullRequestName,
+		AuthorId:          p.AuthorId,
+		Files:             p.Files,
+		Labels:            p.Labels,
+		RequiredReviewers: p.RequiredReviewers,
+		ExcludedReviewers: p.ExcludedReviewers,
+	}
+}
+
+type PullRequestCreateResponse201 struct {
+	Pr PullRequest `json:"pr"`
+}
+
+type PullRequestMergeRequest struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+func (p *PullRequestMergeRequest) Validate() error {
+	if p.PullRequestId == "" {
+		return fmt.Errorf("pull_request_id is required")
+	}
+	return nil
+}
+
+func (p *PullRequestMergeRequest) ToPullRequest() *PullRequest {
+	return &PullRequest{
+		PullRequestId: p.PullRequestId,
+	}
+}
+
+type PullRequestMergeResponse200 struct {
+	Pr PullRequest `json:"pr"`
+}
+
+type PullRequestReassignRequest struct {
+	PullRequestId string `json:"pull_request_id"`
+	OldReviewerId string `json:"old_reviewer_id"`
+}
+
+func (p *PullRequestReassignRequest) Validate() error {
+	if p.PullRequestId == "" {
+		return fmt.Errorf("pull_request_id is required")
+	}
+	if p.OldReviewerId == "" {
+		return fmt.Errorf("old_reviewer_id is required")
+	}
+	return nil
+}
+
+type PullRequestReassignResponse200 struct {
+	Pr         PullRequest `json:"pr"`
+	ReplacedBy string      `json:"replaced_by"`
+}
+
+// PullRequestReviewerRequest adds a reviewer to a pull request or removes one
+// from it.
+type PullRequestReviewerRequest struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+}
+
+func (p *PullRequestReviewerRequest) Validate() error {
+	if p.PullRequestId == "" {
+		return fmt.Errorf("pull_request_id is required")
+	}
+	if p.ReviewerId == "" {
+		return fmt.Errorf("reviewer_id is required")
+	}
+	return nil
+}
+
+type PullRequestReviewerResponse200 struct {
+	Pr PullRequest `json:"pr"`
+}
+
+// MaxBulkCreateSize caps the number of pull requests in one bulk create.
+const MaxBulkCreateSize = 1000
+
+type PullRequestBulkCreateRequest struct {
+	PullRequests []PullRequestCreateRequest `json:"pull_requests"`
+	// Atomic creates either all of the pull requests or none of them.
+	Atomic bool `json:"atomic"`
+}
+
+// Validate checks the request as a whole, items are validated one by one so
+// that each gets a result of its own.
+func (p *PullRequestBulkCreateRequest) Validate() error {
+	if len(p.PullRequests) == 0 {
+		return fmt.Errorf("pull_requests must contain at least one pull request")
+	}
+	if len(p.PullRequests) > MaxBulkCreateSize {
+		return fmt.Errorf("pull_requests must contain at most %d pull requests", MaxBulkCreateSize)
+	}
+	return nil
+}
+
+type BulkItemStatus string
+
+const (
+	BulkItemCreated BulkItemStatus = "CREATED"
+	BulkItemFailed  BulkItemStatus = "FAILED"
+	// BulkItemSkipped marks valid items not created because an atomic
+	// request failed on another item.
+	BulkItemSkipped BulkItemStatus = "SKIPPED"
+)
+
+type PullRequestBulkCreateItem struct {
+	Index         int            `json:"index"`
+	PullRequestId string         `json:"pull_request_id"`
+	Status        BulkItemStatus `json:"status"`
+	Pr            *PullRequest   `json:"pr,omitempty"`
+	Error         *Error         `json:"error,omitempty"`
+}
+
+type PullRequestBulkCreateResponse200 struct {
+	Created int                         `json:"created"`
+	Failed  int                         `json:"failed"`
+	Results []PullRequestBulkCreateItem `json:"results"`
+}
+
+type PullRequestShort struct {
+	PullRequestId   string `json:"pull_request_id" db:"id"`
+	PullRequestName string `json:"pull_request_name" db:"title"`
+	AuthorId        string `json:"author_id" db:"author_id"`
+	Status          Status `json:"status" db:"status"`
+	// DueAt is when the review is due by the SLA of the reviewer's team, nil
+	// without SLA. It is only set on the reviews of a user.
+	DueAt   *time.Time `json:"due_at,omitempty" db:"due_at"`
+	Overdue bool       `json:"overdue" db:"overdue"`
+}
+
+type UsersGetReviewResponse200 struct {
+	UserId       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+}
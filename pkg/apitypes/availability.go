@@ -0,0 +1,83 @@
+package apitypes
+
+import (
+	"fmt"
+	"time"
+)
+
+// Unavailability is a period, such as a vacation, when the user is not
+// picked as a reviewer regardless of the is_active flag. The period starts
+// at StartsAt and ends right before EndsAt.
+type Unavailability struct {
+	Id       int64     `json:"id" db:"id"`
+	UserId   string    `json:"user_id" db:"user_id"`
+	StartsAt time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt   time.Time `json:"ends_at" db:"ends_at"`
+	Reason   string    `json:"reason" db:"reason"`
+}
+
+func validatePeriod(startsAt, endsAt *time.Time) error {
+	if startsAt == nil {
+		return fmt.Errorf("starts_at is required")
+	}
+	if endsAt == nil {
+		return fmt.Errorf("ends_at is required")
+	}
+	if !endsAt.After(*startsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+type UnavailabilityCreateRequest struct {
+	UserId   string     `json:"user_id"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Reason   string     `json:"reason"`
+}
+
+func (u *UnavailabilityCreateRequest) Validate() error {
+	if u.UserId == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	return validatePeriod(u.StartsAt, u.EndsAt)
+}
+
+func (u *UnavailabilityCreateRequest) ToUnavailability() *Unavailability {
+	return &Unavailability{
+		UserId:   u.UserId,
+		StartsAt: *u.StartsAt,
+		EndsAt:   *u.EndsAt,
+		Reason:   u.Reason,
+	}
+}
+
+// UnavailabilityUpdateRequest moves a period or changes its reason, the user
+// of a period can not be changed.
+type UnavailabilityUpdateRequest struct {
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Reason   string     `json:"reason"`
+}
+
+func (u *UnavailabilityUpdateRequest) Validate() error {
+	return validatePeriod(u.StartsAt, u.EndsAt)
+}
+
+func (u *UnavailabilityUpdateRequest) ToUnavailability(id int64) *Unavailability {
+	return &Unavailability{
+		Id:       id,
+		StartsAt: *u.StartsAt,
+		EndsAt:   *u.EndsAt,
+		Reason:   u.Reason,
+	}
+}
+
+type UnavailabilityResponse struct {
+	Period Unavailability `json:"period"`
+}
+
+type UnavailabilityListResponse200 struct {
+	UserId  string           `json:"user_id"`
+	Periods []Unavailability `json:"periods"`
+}
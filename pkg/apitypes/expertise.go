@@ -0,0 +1,134 @@
+package apitypes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Sugyk/avito_test_task/pkg/codeowners"
+)
+
+// UserExpertise lists what a user knows. Reviewers whose tags match the
+// files or the labels of a pull request are preferred.
+type UserExpertise struct {
+	UserId string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+func (u *UserExpertise) Validate() error {
+	if u.UserId == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	for _, tag := range u.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags must not be empty")
+		}
+	}
+	return nil
+}
+
+// Normalize lowers the tags and drops duplicates, tags are matched case
+// insensitively.
+func (u *UserExpertise) Normalize() {
+	u.Tags = NormalizeTags(u.Tags)
+}
+
+type UserExpertiseResponse200 struct {
+	Expertise UserExpertise `json:"expertise"`
+}
+
+// NormalizeTags returns the tags trimmed, lowered, sorted and without
+// duplicates.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// PathRule gives the files matching Pattern to Users and to the users with
+// one of Tags. Patterns follow the CODEOWNERS syntax, and as in CODEOWNERS
+// the last rule matching a file wins. A rule without owners leaves its files
+// without experts.
+type PathRule struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users"`
+	Tags    []string `json:"tags"`
+}
+
+// TeamPathRules are the path rules used to pick reviewers among the members
+// of the team.
+type TeamPathRules struct {
+	TeamName string     `json:"team_name"`
+	Rules    []PathRule `json:"rules"`
+}
+
+func (t *TeamPathRules) Validate() error {
+	if t.TeamName == "" {
+		return fmt.Errorf("team_name is required")
+	}
+	for i, rule := range t.Rules {
+		if _, err := codeowners.ParsePattern(rule.Pattern); err != nil {
+			return fmt.Errorf("rules[%d]: pattern %q: %w", i, rule.Pattern, err)
+		}
+		if slices.Contains(rule.Users, "") {
+			return fmt.Errorf("rules[%d]: users must not be empty", i)
+		}
+		for _, tag := range rule.Tags {
+			if strings.TrimSpace(tag) == "" {
+				return fmt.Errorf("rules[%d]: tags must not be empty", i)
+			}
+		}
+	}
+	return nil
+}
+
+// Normalize normalizes the tags of the rules and replaces missing owner
+// lists by empty ones.
+func (t *TeamPathRules) Normalize() {
+	if t.Rules == nil {
+		t.Rules = []PathRule{}
+	}
+	for i := range t.Rules {
+		t.Rules[i].Tags = NormalizeTags(t.Rules[i].Tags)
+		if t.Rules[i].Users == nil {
+			t.Rules[i].Users = []string{}
+		}
+	}
+}
+
+type TeamPathRulesResponse200 struct {
+	PathRules TeamPathRules `json:"path_rules"`
+}
+
+type TeamCodeownersRequest struct {
+	TeamName string `json:"team_name"`
+	// Content is the CODEOWNERS file, an empty one removes the team's file.
+	Content string `json:"content"`
+}
+
+func (t *TeamCodeownersRequest) Validate() error {
+	if t.TeamName == "" {
+		return fmt.Errorf("team_name is required")
+	}
+	if _, err := codeowners.Parse(strings.NewReader(t.Content)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TeamCodeowners is the CODEOWNERS file of a team as the service reads it.
+// An @login owner is the user with that id and an @org/team owner stands for
+// the users with the team name among their expertise tags. Owners given by
+// email can not be told apart and are listed in IgnoredOwners.
+type TeamCodeowners struct {
+	TeamName      string     `json:"team_name"`
+	Rules         []PathRule `json:"rules"`
+	IgnoredOwners []string   `json:"ignored_owners"`
+}
+
+type TeamCodeownersResponse200 struct {
+	Codeowners TeamCodeowners `json:"codeowners"`
+}
@@ -0,0 +1,20 @@
+package apitypes
+
+import "time"
+
+// OverdueReview is a review of an open pull request that is past its due
+// time. AgeSeconds is how long ago the reviewer was assigned.
+type OverdueReview struct {
+	PullRequestId   string    `json:"pull_request_id" db:"pr_id"`
+	PullRequestName string    `json:"pull_request_name" db:"title"`
+	AuthorId        string    `json:"author_id" db:"author_id"`
+	ReviewerId      string    `json:"reviewer_id" db:"user_id"`
+	TeamName        string    `json:"team_name" db:"team_name"`
+	AssignedAt      time.Time `json:"assigned_at" db:"assigned_at"`
+	DueAt           time.Time `json:"due_at" db:"due_at"`
+	AgeSeconds      int64     `json:"age_seconds" db:"age_seconds"`
+}
+
+type OverdueReviewsResponse200 struct {
+	Reviews []OverdueReview `json:"reviews"`
+}
@@ -0,0 +1,71 @@
+package apitypes
+
+import "fmt"
+
+// TeamSettings tune how reviews of the team's members are handled.
+type TeamSettings struct {
+	TeamName string `json:"team_name" db:"name"`
+	// StaleReviewHours is how long a review may stay with a reviewer before
+	// it is reassigned to another member. Nil disables the reassignment.
+	StaleReviewHours *int `json:"stale_review_hours" db:"stale_review_hours"`
+	// FirstReviewSLAHours is how long a reviewer has to review a pull
+	// request after being assigned to it. Nil means no such SLA.
+	FirstReviewSLAHours *int `json:"first_review_sla_hours" db:"first_review_sla_hours"`
+	// MergeSLAHours is how long a pull request may stay open after its
+	// creation. Nil means no such SLA.
+	MergeSLAHours *int `json:"merge_sla_hours" db:"merge_sla_hours"`
+	// MinReviewers and MaxReviewers bound how many reviewers a pull request
+	// of the team's members gets on creation and may be left with by adding
+	// and removing them by hand. Nil means 0 and ReviewersPerPR.
+	MinReviewers *int `json:"min_reviewers" db:"min_reviewers"`
+	MaxReviewers *int `json:"max_reviewers" db:"max_reviewers"`
+}
+
+// ReviewerLimits returns the minimum and maximum number of reviewers.
+func (t *TeamSettings) ReviewerLimits() (minReviewers, maxReviewers int) {
+	minReviewers, maxReviewers = 0, ReviewersPerPR
+	if t.MinReviewers != nil {
+		minReviewers = *t.MinReviewers
+	}
+	if t.MaxReviewers != nil {
+		maxReviewers = *t.MaxReviewers
+	}
+	return minReviewers, maxReviewers
+}
+
+// ReviewerSlots returns how many reviewers a new pull request gets:
+// ReviewersPerPR clamped to the reviewer limits, but never fewer than its
+// required reviewers.
+func (t *TeamSettings) ReviewerSlots(required int) int {
+	minReviewers, maxReviewers := t.ReviewerLimits()
+	return max(min(max(ReviewersPerPR, minReviewers), maxReviewers), required)
+}
+
+func (t *TeamSettings) Validate() error {
+	if t.TeamName == "" {
+		return fmt.Errorf("team_name is required")
+	}
+	if t.StaleReviewHours != nil && *t.StaleReviewHours <= 0 {
+		return fmt.Errorf("stale_review_hours must be positive")
+	}
+	if t.FirstReviewSLAHours != nil && *t.FirstReviewSLAHours <= 0 {
+		return fmt.Errorf("first_review_sla_hours must be positive")
+	}
+	if t.MergeSLAHours != nil && *t.MergeSLAHours <= 0 {
+		return fmt.Errorf("merge_sla_hours must be positive")
+	}
+	if t.MinReviewers != nil && *t.MinReviewers < 0 {
+		return fmt.Errorf("min_reviewers must not be negative")
+	}
+	if t.MaxReviewers != nil && *t.MaxReviewers <= 0 {
+		return fmt.Errorf("max_reviewers must be positive")
+	}
+	if minReviewers, maxReviewers := t.ReviewerLimits(); minReviewers > maxReviewers {
+		return fmt.Errorf("min_reviewers must not exceed max_reviewers (%d by default)", ReviewersPerPR)
+	}
+	return nil
+}
+
+type TeamSettingsResponse200 struct {
+	Settings TeamSettings `json:"settings"`
+}
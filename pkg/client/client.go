@@ -0,0 +1,452 @@
+// Package client is a typed Go client for the PR reviewer assignment service.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/pkg/apierrors"
+	"github.com/Sugyk/avito_test_task/pkg/apitypes"
+)
+
+// APIError is returned when the service answers with an error response. It
+// unwraps to the matching sentinel from pkg/apierrors, so callers can use
+// errors.Is(err, apierrors.ErrNotFound).
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	// RetryAfter is the delay the service asked for before retrying, set on
+	// RATE_LIMITED errors.
+	RetryAfter time.Duration
+	sentinel   error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	headers    http.Header
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a failed request that is safe to repeat is
+// retried and the initial delay between attempts, which doubles after each
+// retry. Create and reassign are repeated with the same Idempotency-Key.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithHeader adds a header to every request, e.g. an Authorization token.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(key, value)
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		maxRetries: 3,
+		backoff:    200 * time.Millisecond,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) TeamAdd(ctx context.Context, team *apitypes.Team) (*apitypes.Team, error) {
+	var resp apitypes.TeamAddResponse201
+	if err := c.do(ctx, http.MethodPost, "/team/add", nil, team, &resp, retryNever); err != nil {
+		return nil, err
+	}
+	return &resp.Team, nil
+}
+
+func (c *Client) TeamGet(ctx context.Context, teamName string) (*apitypes.Team, error) {
+	var resp apitypes.Team
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/get", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) TeamGetSettings(ctx context.Context, teamName string) (*apitypes.TeamSettings, error) {
+	var resp apitypes.TeamSettingsResponse200
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/settings", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Settings, nil
+}
+
+// TeamSetSettings replaces all settings of the team, the ones left out of
+// settings are reset to their defaults.
+func (c *Client) TeamSetSettings(ctx context.Context, settings apitypes.TeamSettings) (*apitypes.TeamSettings, error) {
+	var resp apitypes.TeamSettingsResponse200
+	if err := c.do(ctx, http.MethodPost, "/team/settings", nil, settings, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Settings, nil
+}
+
+func (c *Client) TeamGetPathRules(ctx context.Context, teamName string) (*apitypes.TeamPathRules, error) {
+	var resp apitypes.TeamPathRulesResponse200
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/pathRules", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.PathRules, nil
+}
+
+// TeamSetPathRules replaces the path rules of the team.
+func (c *Client) TeamSetPathRules(ctx context.Context, rules apitypes.TeamPathRules) (*apitypes.TeamPathRules, error) {
+	var resp apitypes.TeamPathRulesResponse200
+	if err := c.do(ctx, http.MethodPost, "/team/pathRules", nil, rules, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.PathRules, nil
+}
+
+// TeamSetCodeowners replaces the CODEOWNERS file of the team, an empty
+// content removes it.
+func (c *Client) TeamSetCodeowners(ctx context.Context, teamName string, content string) (*apitypes.TeamCodeowners, error) {
+	var resp apitypes.TeamCodeownersResponse200
+	req := apitypes.TeamCodeownersRequest{TeamName: teamName, Content: content}
+	if err := c.do(ctx, http.MethodPost, "/team/codeowners", nil, req, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Codeowners, nil
+}
+
+func (c *Client) UsersSetIsActive(ctx context.Context, userID string, isActive bool) (*apitypes.User, error) {
+	req := apitypes.UsersSetIsActiveRequest{
+		UserId:   userID,
+		IsActive: &isActive,
+	}
+	var resp apitypes.UsersSerIsActiveResponse200
+	if err := c.do(ctx, http.MethodPost, "/users/setIsActive", nil, req, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+func (c *Client) UsersGetReview(ctx context.Context, userID string) ([]apitypes.PullRequestShort, error) {
+	var resp apitypes.UsersGetReviewResponse200
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/getReview", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return resp.PullRequests, nil
+}
+
+func (c *Client) UsersGetExpertise(ctx context.Context, userID string) (*apitypes.UserExpertise, error) {
+	var resp apitypes.UserExpertiseResponse200
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/expertise", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Expertise, nil
+}
+
+// UsersSetExpertise replaces the expertise tags of the user.
+func (c *Client) UsersSetExpertise(ctx context.Context, expertise apitypes.UserExpertise) (*apitypes.UserExpertise, error) {
+	var resp apitypes.UserExpertiseResponse200
+	if err := c.do(ctx, http.MethodPost, "/users/expertise", nil, expertise, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Expertise, nil
+}
+
+// ReviewsOverdue returns the reviews past the SLA of their reviewer's team,
+// of all teams when teamName is empty.
+func (c *Client) ReviewsOverdue(ctx context.Context, teamName string) ([]apitypes.OverdueReview, error) {
+	var resp apitypes.OverdueReviewsResponse200
+	query := url.Values{}
+	if teamName != "" {
+		query.Set("team_name", teamName)
+	}
+	if err := c.do(ctx, http.MethodGet, "/reviews/overdue", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return resp.Reviews, nil
+}
+
+// UsersListAvailability returns the unavailability periods of the user that
+// are not over yet, or all of them with includePast.
+func (c *Client) UsersListAvailability(ctx context.Context, userID string, includePast bool) ([]apitypes.Unavailability, error) {
+	var resp apitypes.UnavailabilityListResponse200
+	query := url.Values{"user_id": {userID}}
+	if includePast {
+		query.Set("include_past", "true")
+	}
+	if err := c.do(ctx, http.MethodGet, "/users/availability", query, nil, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return resp.Periods, nil
+}
+
+func (c *Client) UsersAddAvailability(ctx context.Context, req apitypes.UnavailabilityCreateRequest) (*apitypes.Unavailability, error) {
+	var resp apitypes.UnavailabilityResponse
+	if err := c.do(ctx, http.MethodPost, "/users/availability", nil, req, &resp, retryNever); err != nil {
+		return nil, err
+	}
+	return &resp.Period, nil
+}
+
+func (c *Client) UsersUpdateAvailability(ctx context.Context, id int64, req apitypes.UnavailabilityUpdateRequest) (*apitypes.Unavailability, error) {
+	var resp apitypes.UnavailabilityResponse
+	path := "/users/availability/" + strconv.FormatInt(id, 10)
+	if err := c.do(ctx, http.MethodPut, path, nil, req, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Period, nil
+}
+
+// UsersDeleteAvailability deletes the period and returns it. It is not
+// retried, since a retry of a delete that went through fails as not found.
+func (c *Client) UsersDeleteAvailability(ctx context.Context, id int64) (*apitypes.Unavailability, error) {
+	var resp apitypes.UnavailabilityResponse
+	path := "/users/availability/" + strconv.FormatInt(id, 10)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, &resp, retryNever); err != nil {
+		return nil, err
+	}
+	return &resp.Period, nil
+}
+
+func (c *Client) PullRequestCreate(ctx context.Context, req apitypes.PullRequestCreateRequest) (*apitypes.PullRequest, error) {
+	var resp apitypes.PullRequestCreateResponse201
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/create", nil, req, &resp, retryWithKey); err != nil {
+		return nil, err
+	}
+	return &resp.Pr, nil
+}
+
+func (c *Client) PullRequestMerge(ctx context.Context, prID string) (*apitypes.PullRequest, error) {
+	req := apitypes.PullRequestMergeRequest{PullRequestId: prID}
+	var resp apitypes.PullRequestMergeResponse200
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/merge", nil, req, &resp, retrySafe); err != nil {
+		return nil, err
+	}
+	return &resp.Pr, nil
+}
+
+func (c *Client) PullRequestReassign(ctx context.Context, prID, oldReviewerID string) (*apitypes.PullRequest, string, error) {
+	req := apitypes.PullRequestReassignRequest{
+		PullRequestId: prID,
+		OldReviewerId: oldReviewerID,
+	}
+	var resp apitypes.PullRequestReassignResponse200
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, req, &resp, retryWithKey); err != nil {
+		return nil, "", err
+	}
+	return &resp.Pr, resp.ReplacedBy, nil
+}
+
+// PullRequestAddReviewer assigns one more reviewer to an open pull request.
+func (c *Client) PullRequestAddReviewer(ctx context.Context, prID, reviewerID string) (*apitypes.PullRequest, error) {
+	req := apitypes.PullRequestReviewerRequest{PullRequestId: prID, ReviewerId: reviewerID}
+	var resp apitypes.PullRequestReviewerResponse200
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/addReviewer", nil, req, &resp, retryWithKey); err != nil {
+		return nil, err
+	}
+	return &resp.Pr, nil
+}
+
+// PullRequestRemoveReviewer unassigns a reviewer from an open pull request.
+func (c *Client) PullRequestRemoveReviewer(ctx context.Context, prID, reviewerID string) (*apitypes.PullRequest, error) {
+	req := apitypes.PullRequestReviewerRequest{PullRequestId: prID, ReviewerId: reviewerID}
+	var resp apitypes.PullRequestReviewerResponse200
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/removeReviewer", nil, req, &resp, retryWithKey); err != nil {
+		return nil, err
+	}
+	return &resp.Pr, nil
+}
+
+// PullRequestBulkCreate creates many pull requests at once. Failures of single
+// pull requests are reported in the results, not as an error.
+func (c *Client) PullRequestBulkCreate(ctx context.Context, req apitypes.PullRequestBulkCreateRequest) (*apitypes.PullRequestBulkCreateResponse200, error) {
+	var resp apitypes.PullRequestBulkCreateResponse200
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/bulkCreate", nil, req, &resp, retryWithKey); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+type retryPolicy int
+
+const (
+	// retryNever is for requests whose replay changes the result.
+	retryNever retryPolicy = iota
+	// retrySafe is for idempotent requests.
+	retrySafe
+	// retryWithKey is for requests made idempotent by an Idempotency-Key,
+	// which stays the same across retries.
+	retryWithKey
+)
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey makes the request sent with ctx use key as its
+// Idempotency-Key instead of a random one. Reusing a key across runs of e.g. a
+// CI job keeps a retried job from creating or reassigning twice.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyCtx{}).(string); ok && key != "" {
+		return key
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// do sends the request and decodes the response into out. Requests are
+// retried according to policy, since replaying e.g. a reassign without an
+// idempotency key changes the result. Rate limited requests never reached the
+// handlers, so every request is retried after the delay the service asks for.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any, policy retryPolicy) error {
+	var body []byte
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	header := make(http.Header)
+	if policy == retryWithKey {
+		header.Set("Idempotency-Key", idempotencyKey(ctx))
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, target, header, body, out)
+		if err == nil || attempt >= c.maxRetries {
+			return err
+		}
+		delay := backoff
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests:
+			delay = max(delay, apiErr.RetryAfter)
+		case policy == retryNever || !retryable(err):
+			return err
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, body []byte, out any) error {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	var errResp apitypes.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Code == "" {
+		apiErr.Code = apierrors.CodeInternal
+		apiErr.Message = http.StatusText(resp.StatusCode)
+		return apiErr
+	}
+	apiErr.Code = errResp.Error.Code
+	apiErr.Message = errResp.Error.Message
+	apiErr.sentinel = apierrors.FromCode(apiErr.Code, apiErr.Message)
+	return apiErr
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// transport failures, the request may not have reached the service
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,366 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sugyk/avito_test_task/internal/api"
+	"github.com/Sugyk/avito_test_task/internal/api/handlers"
+	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/pkg/apierrors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestServer(t *testing.T) (*handlers.MockService, *Client) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := handlers.NewMockService(ctrl)
+
+	router, err := api.NewRouter("0", handlers.NewHandler(mockService, slog.Default()))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router.Handler())
+	t.Cleanup(server.Close)
+
+	return mockService, New(server.URL, WithRetries(2, time.Millisecond))
+}
+
+func TestClient_PullRequestCreate(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	req := models.PullRequestCreateRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	}
+	expected := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+	}
+	mockService.EXPECT().PullRequestCreate(gomock.Any(), req.ToPullRequest()).Return(expected, nil)
+
+	pr, err := c.PullRequestCreate(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, expected, pr)
+}
+
+func TestClient_TeamGet(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	active := true
+	expected := &models.Team{
+		TeamName: "backend",
+		Members:  []models.TeamMember{{UserId: "u1", Username: "Alice", IsActive: &active}},
+	}
+	mockService.EXPECT().GetTeamWithMembers(gomock.Any(), "backend").Return(expected, nil)
+
+	team, err := c.TeamGet(context.Background(), "backend")
+	require.NoError(t, err)
+	require.Equal(t, expected, team)
+}
+
+func TestClient_PullRequestReassign(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	expected := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u3"}}
+	mockService.EXPECT().PullRequestReassign(gomock.Any(), "pr-1", "u2").Return(expected, "u3", nil)
+
+	pr, replacedBy, err := c.PullRequestReassign(context.Background(), "pr-1", "u2")
+	require.NoError(t, err)
+	require.Equal(t, expected, pr)
+	require.Equal(t, "u3", replacedBy)
+}
+
+func TestClient_PullRequestAddRemoveReviewer(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	added := &models.PullRequest{PullRequestId: "pr-1", Status: models.StatusOpen, AssignedReviewers: []string{"u2", "u3"}}
+	mockService.EXPECT().PullRequestAddReviewer(gomock.Any(), "pr-1", "u3").Return(added, nil)
+	mockService.EXPECT().PullRequestAddReviewer(gomock.Any(), "pr-1", "u4").Return(nil, models.ErrTooManyReviewers)
+	mockService.EXPECT().PullRequestRemoveReviewer(gomock.Any(), "pr-1", "u2").Return(nil, models.ErrTooFewReviewers)
+
+	pr, err := c.PullRequestAddReviewer(context.Background(), "pr-1", "u3")
+	require.NoError(t, err)
+	require.Equal(t, added, pr)
+
+	_, err = c.PullRequestAddReviewer(context.Background(), "pr-1", "u4")
+	require.ErrorIs(t, err, apierrors.ErrReviewerLimit)
+	_, err = c.PullRequestRemoveReviewer(context.Background(), "pr-1", "u2")
+	require.ErrorIs(t, err, apierrors.ErrReviewerLimit)
+}
+
+func TestClient_MapsErrorCodesToSentinels(t *testing.T) {
+	tests := []struct {
+		name    string
+		svcErr  error
+		wantErr error
+		code    string
+	}{
+		{name: "pr not found", svcErr: models.ErrPRNotFound, wantErr: apierrors.ErrNotFound, code: apierrors.CodeNotFound},
+		{name: "user not found", svcErr: models.ErrUserNotFound, wantErr: apierrors.ErrNotFound, code: apierrors.CodeNotFound},
+		{name: "merged", svcErr: models.ErrReassigningMergedPR, wantErr: apierrors.ErrPRMerged, code: apierrors.CodePrMerged},
+		{name: "no candidate", svcErr: models.ErrNoActiveCandidates, wantErr: apierrors.ErrNoCandidate, code: apierrors.CodeNoCandidate},
+		{name: "internal", svcErr: errors.New("boom"), wantErr: apierrors.ErrInternalError, code: apierrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService, c := newTestServer(t)
+			mockService.EXPECT().PullRequestReassign(gomock.Any(), "pr-1", "u2").Return(nil, "", tt.svcErr)
+
+			_, _, err := c.PullRequestReassign(context.Background(), "pr-1", "u2")
+			require.ErrorIs(t, err, tt.wantErr)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			require.Equal(t, tt.code, apiErr.Code)
+		})
+	}
+}
+
+func TestClient_UnknownErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": {"code": "SOMETHING_NEW", "message": "something new"}}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).TeamGet(context.Background(), "backend")
+	var codeErr *apierrors.Error
+	require.ErrorAs(t, err, &codeErr)
+	require.Equal(t, &apierrors.Error{Code: "SOMETHING_NEW", Message: "something new"}, codeErr)
+}
+
+func TestClient_RequiredReviewerErrors(t *testing.T) {
+	mockService, c := newTestServer(t)
+	mockService.EXPECT().PullRequestCreate(gomock.Any(), gomock.Any()).Return(nil, models.ErrReviewerUnavailable)
+	mockService.EXPECT().PullRequestCreate(gomock.Any(), gomock.Any()).Return(nil, models.ErrReviewerNotFound)
+
+	req := models.PullRequestCreateRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", RequiredReviewers: []string{"u5"}}
+	_, err := c.PullRequestCreate(context.Background(), req)
+	require.ErrorIs(t, err, apierrors.ErrInvalidInput)
+	_, err = c.PullRequestCreate(context.Background(), req)
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"user_id": "u1", "pull_requests": []}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	prs, err := c.UsersGetReview(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Empty(t, prs)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	_, err := c.TeamAdd(context.Background(), &models.Team{TeamName: "backend"})
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RetriesWithIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"pr": {"pull_request_id": "pr-1"}, "replaced_by": "u3"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	_, _, err := c.PullRequestReassign(context.Background(), "pr-1", "u2")
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	require.NotEmpty(t, keys[0])
+	require.Equal(t, keys[0], keys[1])
+	require.Equal(t, keys[0], keys[2])
+
+	keys = nil
+	ctx := WithIdempotencyKey(context.Background(), "ci-run-42")
+	_, _, err = c.PullRequestReassign(ctx, "pr-1", "u2")
+	require.NoError(t, err)
+	require.Equal(t, "ci-run-42", keys[0])
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	_, c := newTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.TeamGet(ctx, "backend")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"code": "RATE_LIMITED", "message": "rate limit exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pr": {"pull_request_id": "pr-1"}, "replaced_by": "u3"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	_, replacedBy, err := c.PullRequestReassign(context.Background(), "pr-1", "u2")
+	require.NoError(t, err)
+	require.Equal(t, "u3", replacedBy)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_RateLimitedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error": {"code": "RATE_LIMITED", "message": "rate limit exceeded"}}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithRetries(0, 0))
+	_, err := c.TeamGet(context.Background(), "backend")
+	require.ErrorIs(t, err, apierrors.ErrRateLimited)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, 7*time.Second, apiErr.RetryAfter)
+}
+
+func TestClient_Availability(t *testing.T) {
+	mockService, c := newTestServer(t)
+	ctx := context.Background()
+
+	startsAt := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(14 * 24 * time.Hour)
+	period := &models.Unavailability{Id: 7, UserId: "u1", StartsAt: startsAt, EndsAt: endsAt, Reason: "vacation"}
+
+	mockService.EXPECT().
+		CreateUnavailability(gomock.Any(), &models.Unavailability{UserId: "u1", StartsAt: startsAt, EndsAt: endsAt, Reason: "vacation"}).
+		Return(period, nil)
+	created, err := c.UsersAddAvailability(ctx, models.UnavailabilityCreateRequest{
+		UserId: "u1", StartsAt: &startsAt, EndsAt: &endsAt, Reason: "vacation",
+	})
+	require.NoError(t, err)
+	require.Equal(t, period, created)
+
+	mockService.EXPECT().ListUnavailability(gomock.Any(), "u1", true).Return([]models.Unavailability{*period}, nil)
+	periods, err := c.UsersListAvailability(ctx, "u1", true)
+	require.NoError(t, err)
+	require.Equal(t, []models.Unavailability{*period}, periods)
+
+	mockService.EXPECT().DeleteUnavailability(gomock.Any(), int64(7)).Return(nil, models.ErrPeriodNotFound)
+	_, err = c.UsersDeleteAvailability(ctx, 7)
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestClient_TeamSettings(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	hours := 48
+	settings := models.TeamSettings{TeamName: "backend", StaleReviewHours: &hours}
+	mockService.EXPECT().SetTeamSettings(gomock.Any(), &settings).Return(&settings, nil)
+
+	updated, err := c.TeamSetSettings(context.Background(), settings)
+	require.NoError(t, err)
+	require.Equal(t, &settings, updated)
+
+	mockService.EXPECT().GetTeamSettings(gomock.Any(), "ghost").Return(nil, models.ErrTeamNotFound)
+	_, err = c.TeamGetSettings(context.Background(), "ghost")
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestClient_ReviewsOverdue(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	reviews := []models.OverdueReview{{
+		PullRequestId: "pr-1",
+		ReviewerId:    "u2",
+		TeamName:      "backend",
+		AssignedAt:    time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC),
+		DueAt:         time.Date(2026, 8, 2, 12, 0, 0, 0, time.UTC),
+		AgeSeconds:    172800,
+	}}
+	mockService.EXPECT().ReviewsOverdue(gomock.Any(), "").Return(reviews, nil)
+
+	got, err := c.ReviewsOverdue(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, reviews, got)
+
+	mockService.EXPECT().ReviewsOverdue(gomock.Any(), "ghost").Return(nil, models.ErrTeamNotFound)
+	_, err = c.ReviewsOverdue(context.Background(), "ghost")
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestClient_Expertise(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	expertise := models.UserExpertise{UserId: "u1", Tags: []string{"api"}}
+	mockService.EXPECT().SetUserExpertise(gomock.Any(), &expertise).Return(&expertise, nil)
+	updated, err := c.UsersSetExpertise(context.Background(), expertise)
+	require.NoError(t, err)
+	require.Equal(t, &expertise, updated)
+
+	rules := models.TeamPathRules{
+		TeamName: "backend",
+		Rules:    []models.PathRule{{Pattern: "*.sql", Users: []string{}, Tags: []string{"sql"}}},
+	}
+	mockService.EXPECT().GetTeamPathRules(gomock.Any(), "backend").Return(&rules, nil)
+	got, err := c.TeamGetPathRules(context.Background(), "backend")
+	require.NoError(t, err)
+	require.Equal(t, &rules, got)
+
+	mockService.EXPECT().GetUserExpertise(gomock.Any(), "ghost").Return(nil, models.ErrUserNotFound)
+	_, err = c.UsersGetExpertise(context.Background(), "ghost")
+	require.ErrorIs(t, err, apierrors.ErrNotFound)
+}
+
+func TestClient_TeamSetCodeowners(t *testing.T) {
+	mockService, c := newTestServer(t)
+
+	codeowners := &models.TeamCodeowners{
+		TeamName:      "backend",
+		Rules:         []models.PathRule{{Pattern: "*", Users: []string{"u1"}, Tags: []string{}}},
+		IgnoredOwners: []string{},
+	}
+	mockService.EXPECT().
+		SetTeamCodeowners(gomock.Any(), &models.TeamCodeownersRequest{TeamName: "backend", Content: "* @u1\n"}).
+		Return(codeowners, nil)
+	got, err := c.TeamSetCodeowners(context.Background(), "backend", "* @u1\n")
+	require.NoError(t, err)
+	require.Equal(t, codeowners, got)
+
+	_, err = c.TeamSetCodeowners(context.Background(), "backend", "!* @u1\n")
+	require.ErrorIs(t, err, apierrors.ErrInvalidInput)
+}
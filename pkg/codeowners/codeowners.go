@@ -0,0 +1,171 @@
+// Package codeowners parses CODEOWNERS files in the GitHub format and tells
+// who owns a path.
+//
+// Every line holds a path pattern followed by its owners, separated by
+// whitespace. Lines starting with # are comments, and so is the rest of a
+// line from a # starting an owner. The last line whose pattern matches a
+// path gives its owners, a line without owners leaves the path unowned.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type OwnerKind int
+
+const (
+	// User is a GitHub user, @login.
+	User OwnerKind = iota
+	// Team is a team of an organization, @org/team.
+	Team
+	// Email is a user given by email address.
+	Email
+)
+
+type Owner struct {
+	Kind OwnerKind
+	// Name is the login of a user, the name of a team without its
+	// organization, or an email address.
+	Name string
+	// Org is the organization of a team.
+	Org string
+}
+
+func (o Owner) String() string {
+	switch o.Kind {
+	case Team:
+		return "@" + o.Org + "/" + o.Name
+	case Email:
+		return o.Name
+	default:
+		return "@" + o.Name
+	}
+}
+
+var (
+	userOwner  = regexp.MustCompile(`^@([A-Za-z0-9](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?)$`)
+	teamOwner  = regexp.MustCompile(`^@([A-Za-z0-9][A-Za-z0-9_.-]*)/([A-Za-z0-9][A-Za-z0-9_.-]*)$`)
+	emailOwner = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// ParseOwner parses an owner of a CODEOWNERS line.
+func ParseOwner(s string) (Owner, error) {
+	if m := teamOwner.FindStringSubmatch(s); m != nil {
+		return Owner{Kind: Team, Org: m[1], Name: m[2]}, nil
+	}
+	if m := userOwner.FindStringSubmatch(s); m != nil {
+		return Owner{Kind: User, Name: m[1]}, nil
+	}
+	if emailOwner.MatchString(s) {
+		return Owner{Kind: Email, Name: s}, nil
+	}
+	return Owner{}, fmt.Errorf("invalid owner %q, owners are @user, @org/team or an email address", s)
+}
+
+// Rule is a line of a CODEOWNERS file.
+type Rule struct {
+	// Line is the line number, starting at 1.
+	Line    int
+	Pattern Pattern
+	Owners  []Owner
+}
+
+type File struct {
+	Rules []Rule
+}
+
+// ParseError lists the invalid lines of a file.
+type ParseError struct {
+	Lines []LineError
+}
+
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *ParseError) Error() string {
+	messages := make([]string, 0, len(e.Lines))
+	for _, lineErr := range e.Lines {
+		messages = append(messages, lineErr.Error())
+	}
+	return "codeowners: " + strings.Join(messages, "; ")
+}
+
+// maxLineErrors bounds the errors reported for a file that is not a
+// CODEOWNERS file at all.
+const maxLineErrors = 10
+
+// Parse reads a CODEOWNERS file. All the invalid lines are reported in a
+// *ParseError, up to a limit.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	parseErr := &ParseError{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		rule, ok, err := parseLine(scanner.Text())
+		if err != nil {
+			if len(parseErr.Lines) < maxLineErrors {
+				parseErr.Lines = append(parseErr.Lines, LineError{Line: line, Err: err})
+			}
+			continue
+		}
+		if ok {
+			rule.Line = line
+			f.Rules = append(f.Rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("codeowners: %w", err)
+	}
+	if len(parseErr.Lines) > 0 {
+		return nil, parseErr
+	}
+	return f, nil
+}
+
+// parseLine parses a line, ok is false for blank lines and comments.
+func parseLine(line string) (rule Rule, ok bool, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return Rule{}, false, nil
+	}
+	if strings.HasPrefix(fields[0], "[") {
+		return Rule{}, false, errors.New("sections are not supported")
+	}
+	rule.Pattern, err = ParsePattern(fields[0])
+	if err != nil {
+		return Rule{}, false, err
+	}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		owner, err := ParseOwner(field)
+		if err != nil {
+			return Rule{}, false, err
+		}
+		rule.Owners = append(rule.Owners, owner)
+	}
+	return rule, true, nil
+}
+
+// Match returns the rule giving the owners of path, nil when no rule
+// matches it.
+func (f *File) Match(path string) *Rule {
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].Pattern.Match(path) {
+			return &f.Rules[i]
+		}
+	}
+	return nil
+}
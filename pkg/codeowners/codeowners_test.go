@@ -0,0 +1,109 @@
+package codeowners
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `# This is a comment.
+# Each line is a file pattern followed by one or more owners.
+
+# These owners will be the default owners for everything in the repo.
+*       @global-owner1 @global-owner2
+
+# Order is important; the last matching pattern takes the most precedence.
+*.js    @js-owner #This is an inline comment.
+*.go docs@example.com
+
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/ @octocat
+/docs/ @doctocat
+/apps/ @octocat
+/apps/github
+
+/scripts/ @doctocat @octo-org/octocats
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+	require.Len(t, f.Rules, 10)
+
+	require.Equal(t, 5, f.Rules[0].Line)
+	require.Equal(t, "*", f.Rules[0].Pattern.String())
+	require.Equal(t, []Owner{{Kind: User, Name: "global-owner1"}, {Kind: User, Name: "global-owner2"}}, f.Rules[0].Owners)
+	require.Equal(t, []Owner{{Kind: User, Name: "js-owner"}}, f.Rules[1].Owners)
+	require.Equal(t, []Owner{{Kind: Email, Name: "docs@example.com"}}, f.Rules[2].Owners)
+	require.Empty(t, f.Rules[8].Owners)
+	require.Equal(t, []Owner{{Kind: User, Name: "doctocat"}, {Kind: Team, Org: "octo-org", Name: "octocats"}}, f.Rules[9].Owners)
+}
+
+func TestFile_Match(t *testing.T) {
+	f, err := Parse(strings.NewReader(sample))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		line int
+	}{
+		{path: "README.md", line: 5},
+		{path: "web/app.js", line: 8},
+		{path: "cmd/main.go", line: 9},
+		{path: "build/logs/today.log", line: 11},
+		{path: "docs/getting-started.md", line: 14},
+		{path: "docs/build-app/troubleshooting.md", line: 14},
+		{path: "apps/github/main.go", line: 16},
+		{path: "apps/web/main.go", line: 15},
+		{path: "scripts/deploy.sh", line: 18},
+	}
+	for _, tt := range tests {
+		rule := f.Match(tt.path)
+		require.NotNil(t, rule, tt.path)
+		require.Equal(t, tt.line, rule.Line, tt.path)
+	}
+
+	empty, err := Parse(strings.NewReader("# nothing\n"))
+	require.NoError(t, err)
+	require.Nil(t, empty.Match("README.md"))
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse(strings.NewReader("*.go @ok\n!*.md @owner\n\n[Section]\n*.js not-an-owner\n"))
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	lines := []int{}
+	for _, lineErr := range parseErr.Lines {
+		lines = append(lines, lineErr.Line)
+	}
+	require.Equal(t, []int{2, 4, 5}, lines)
+	require.Contains(t, err.Error(), "line 5: invalid owner")
+}
+
+func TestParseOwner(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Owner
+		wantErr bool
+	}{
+		{in: "@octocat", want: Owner{Kind: User, Name: "octocat"}},
+		{in: "@octo-org/octocats", want: Owner{Kind: Team, Org: "octo-org", Name: "octocats"}},
+		{in: "user@example.com", want: Owner{Kind: Email, Name: "user@example.com"}},
+		{in: "octocat", wantErr: true},
+		{in: "@", wantErr: true},
+		{in: "@org/", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOwner(tt.in)
+		if tt.wantErr {
+			require.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.want, got)
+		require.Equal(t, tt.in, got.String())
+	}
+}
@@ -0,0 +1,90 @@
+package codeowners
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a CODEOWNERS path pattern:
+//   - a pattern with a slash at the start or in the middle is relative to
+//     the repository root, otherwise it matches at any depth
+//   - * and ? match within a path segment, ** matches across segments
+//   - a pattern whose last segment has no wildcard also matches everything
+//     under the directory it names, a trailing slash matches only that
+//
+// As on GitHub, negation with ! and character ranges are not supported.
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+// ParsePattern parses a pattern. A leading \# stands for a literal #.
+func ParsePattern(pattern string) (Pattern, error) {
+	switch {
+	case pattern == "":
+		return Pattern{}, errors.New("empty pattern")
+	case strings.HasPrefix(pattern, "!"):
+		return Pattern{}, errors.New("negated patterns are not supported")
+	case strings.ContainsAny(pattern, "[]"):
+		return Pattern{}, errors.New("character ranges are not supported")
+	case strings.ContainsAny(pattern, " \t\r\n"):
+		return Pattern{}, errors.New("pattern must not contain whitespace")
+	}
+
+	trimmed := strings.TrimPrefix(pattern, `\`)
+	dirOnly := strings.HasSuffix(trimmed, "/")
+	trimmed = strings.TrimSuffix(trimmed, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	lastSegment := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*")
+	case !strings.ContainsAny(lastSegment, "*?"):
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return Pattern{raw: pattern, re: regexp.MustCompile(expr.String())}, nil
+}
+
+// MustParsePattern is like ParsePattern but panics on an invalid pattern.
+func MustParsePattern(pattern string) Pattern {
+	p, err := ParsePattern(pattern)
+	if err != nil {
+		panic("codeowners: " + pattern + ": " + err.Error())
+	}
+	return p
+}
+
+// Match tells if the pattern covers path, a path relative to the
+// repository root with or without a leading slash.
+func (p Pattern) Match(path string) bool {
+	return p.re != nil && p.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
@@ -0,0 +1,85 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "*.go",
+			matches: []string{"main.go", "internal/api/server.go"},
+			misses:  []string{"main.go.orig", "README.md"},
+		},
+		{
+			pattern: "/docs/",
+			matches: []string{"docs/index.md", "docs/api/spec.md"},
+			misses:  []string{"docs", "internal/docs/index.md"},
+		},
+		{
+			pattern: "apps/",
+			matches: []string{"apps/web/main.js", "services/apps/app.go"},
+			misses:  []string{"apps", "myapps/app.go"},
+		},
+		{
+			pattern: "docs/*",
+			matches: []string{"docs/index.md"},
+			misses:  []string{"docs/api/spec.md", "internal/docs/index.md"},
+		},
+		{
+			pattern: "internal/api",
+			matches: []string{"internal/api", "internal/api/server.go", "internal/api/handlers/users.go"},
+			misses:  []string{"internal/apierrors/errors.go", "pkg/internal/api/x.go"},
+		},
+		{
+			pattern: "**/migrations",
+			matches: []string{"migrations/1.sql", "internal/migrations/1.sql"},
+			misses:  []string{"internal/migration.go"},
+		},
+		{
+			pattern: "internal/**/*_test.go",
+			matches: []string{"internal/a_test.go", "internal/api/handlers/handlers_test.go"},
+			misses:  []string{"pkg/client/client_test.go"},
+		},
+		{
+			pattern: "pkg/**",
+			matches: []string{"pkg/client/client.go"},
+			misses:  []string{"pkg", "internal/pkg/x.go"},
+		},
+		{
+			pattern: `\#notes`,
+			matches: []string{"#notes", "docs/#notes/a.md"},
+			misses:  []string{"notes"},
+		},
+		{
+			pattern: "file?.txt",
+			matches: []string{"file1.txt", "a/fileA.txt"},
+			misses:  []string{"file10.txt", "file/.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			require.NoError(t, err)
+			for _, path := range tt.matches {
+				require.True(t, p.Match(path), path)
+			}
+			for _, path := range tt.misses {
+				require.False(t, p.Match(path), path)
+			}
+		})
+	}
+}
+
+func TestParsePattern_Invalid(t *testing.T) {
+	for _, pattern := range []string{"", "!*.go", "*.[ch]", "a b"} {
+		_, err := ParsePattern(pattern)
+		require.Error(t, err, pattern)
+	}
+}
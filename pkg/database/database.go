@@ -3,54 +3,125 @@ package database
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/pgx"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/XSAM/otelsql"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-func NewDbConnection(ctx context.Context) (*sqlx.DB, error) {
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		os.Getenv("DB_USERNAME"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_ADDRESS"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_SSLMODE"),
-	)
+type Config struct {
+	Username        string
+	Password        string
+	Address         string
+	Port            string
+	Name            string
+	SSLMode         string
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	// StatementTimeout aborts statements running longer, zero disables it.
+	StatementTimeout time.Duration
+	// ConnectTimeout bounds a single connection attempt.
+	ConnectTimeout time.Duration
+	// StartupTimeout is how long NewDbConnection keeps retrying to reach
+	// the database, zero means a single attempt.
+	StartupTimeout time.Duration
+}
+
+const (
+	startupMinBackoff = 500 * time.Millisecond
+	startupMaxBackoff = 5 * time.Second
+)
 
-	pool, err := pgxpool.New(ctx, connStr)
+// URL returns the connection string of the database.
+func (c Config) URL() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     net.JoinHostPort(c.Address, c.Port),
+		Path:     "/" + c.Name,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
+
+func NewDbConnection(ctx context.Context, cfg Config, logger *slog.Logger) (*sqlx.DB, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL())
+	if err != nil {
+		return nil, fmt.Errorf("parse database config: %w", err)
+	}
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	poolConfig.MinConns = cfg.MinConns
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.ConnectTimeout > 0 {
+		poolConfig.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := pool.Ping(ctx); err != nil {
+	if err := waitForDatabase(ctx, pool.Ping, cfg.StartupTimeout, logger); err != nil {
+		pool.Close()
 		return nil, err
 	}
 
-	db := sqlx.NewDb(stdlib.OpenDBFromPool(pool), "pgx")
+	// every query gets its own span via the otelsql wrapped connector
+	sqlDB := otelsql.OpenDB(
+		stdlib.GetPoolConnector(pool),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
+	db := sqlx.NewDb(sqlDB, "pgx")
 	return db, nil
 }
 
-func RunMigrations(db *sqlx.DB) error {
-	driver, err := pgx.WithInstance(db.DB, &pgx.Config{})
-	if err != nil {
-		return err
-	}
+// waitForDatabase calls ping until it succeeds, backing off exponentially,
+// so the service can start before Postgres accepts connections.
+func waitForDatabase(ctx context.Context, ping func(context.Context) error, timeout time.Duration, logger *slog.Logger) error {
+	deadline := time.Now().Add(timeout)
+	backoff := startupMinBackoff
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("database is unreachable after %d attempts: %w", attempt, err)
+		}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file:///migrations",
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return err
+		logger.Warn("database is unreachable, retrying", "attempt", attempt, "backoff", backoff, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for database: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, startupMaxBackoff)
 	}
+}
 
-	return m.Up()
+func Ping(ctx context.Context, db *sqlx.DB) error {
+	return db.PingContext(ctx)
 }
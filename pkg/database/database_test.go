@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestConfigURL(t *testing.T) {
+	cfg := Config{
+		Username: "pr",
+		Password: "p@ss/word",
+		Address:  "db",
+		Port:     "5432",
+		Name:     "prs",
+		SSLMode:  "disable",
+	}
+	u, err := url.Parse(cfg.URL())
+	require.NoError(t, err)
+	password, _ := u.User.Password()
+	require.Equal(t, "p@ss/word", password)
+	require.Equal(t, "db:5432", u.Host)
+	require.Equal(t, "/prs", u.Path)
+	require.Equal(t, "disable", u.Query().Get("sslmode"))
+}
+
+func TestWaitForDatabase_RetriesUntilUp(t *testing.T) {
+	calls := 0
+	ping := func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	require.NoError(t, waitForDatabase(context.Background(), ping, 10*time.Second, discard))
+	require.Equal(t, 3, calls)
+}
+
+func TestWaitForDatabase_GivesUpAtDeadline(t *testing.T) {
+	calls := 0
+	ping := func(context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	}
+	err := waitForDatabase(context.Background(), ping, 0, discard)
+	require.ErrorContains(t, err, "connection refused")
+	require.Equal(t, 1, calls)
+}
+
+func TestWaitForDatabase_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ping := func(context.Context) error { return errors.New("connection refused") }
+	err := waitForDatabase(ctx, ping, time.Minute, discard)
+	require.ErrorIs(t, err, context.Canceled)
+}
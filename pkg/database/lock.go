@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// unlockTimeout bounds releasing a lock, which also happens after the
+// context of the holder is canceled.
+const unlockTimeout = 5 * time.Second
+
+// TryAdvisoryLock takes the session level Postgres advisory lock key on a
+// connection of its own, so that only one of several replicas holds it at a
+// time. ok is false when another session holds the lock. Once taken, the
+// lock is held until unlock is called.
+func TryAdvisoryLock(ctx context.Context, db *sqlx.DB, key int64) (unlock func(), ok bool, err error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+	if err := conn.GetContext(ctx, &ok, `SELECT pg_try_advisory_lock($1)`, key); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			// a connection still holding the lock must not go back to the
+			// pool; closing it ends the session and so releases the lock
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNoChange is returned by Migrator methods when the schema already is at
+// the requested version.
+var ErrNoChange = migrate.ErrNoChange
+
+// Migrator applies schema migrations. It uses a connection of its own, so it
+// can be closed without affecting the connection pool of the service.
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+// MigrationStatus describes the schema version of the database.
+type MigrationStatus struct {
+	// Version is the applied version, zero when nothing was applied.
+	Version uint
+	Dirty   bool
+	// Available lists all versions found in the migration source.
+	Available []uint
+}
+
+// Latest returns the highest available version.
+func (s MigrationStatus) Latest() uint {
+	if len(s.Available) == 0 {
+		return 0
+	}
+	return s.Available[len(s.Available)-1]
+}
+
+func NewMigrator(cfg Config, migrations fs.FS) (*Migrator, error) {
+	src, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("open migrations: %w", err)
+	}
+
+	db, err := sql.Open("pgx/v5", cfg.URL())
+	if err != nil {
+		return nil, err
+	}
+	driver, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+	return &Migrator{m: m, source: src}, nil
+}
+
+// MigrationsFS returns the migrations found in dir, or embedded when dir is
+// empty.
+func MigrationsFS(dir string, embedded fs.FS) fs.FS {
+	if dir == "" {
+		return embedded
+	}
+	return os.DirFS(dir)
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return m.m.Up()
+}
+
+// Down rolls back the last n migrations.
+func (m *Migrator) Down(n int) error {
+	if n < 1 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+	return m.m.Steps(-n)
+}
+
+// Goto migrates up or down to version.
+func (m *Migrator) Goto(version uint) error {
+	return m.m.Migrate(version)
+}
+
+// Force sets the version without running migrations, clearing the dirty
+// flag. It is meant for recovery after a failed migration was fixed by hand.
+// Version -1 means no migration applied.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+func (m *Migrator) Status() (MigrationStatus, error) {
+	var status MigrationStatus
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return status, err
+	}
+	status.Version, status.Dirty = version, dirty
+
+	status.Available, err = availableVersions(m.source)
+	return status, err
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// LatestMigration returns the highest version found in migrations.
+func LatestMigration(migrations fs.FS) (uint, error) {
+	src, err := iofs.New(migrations, ".")
+	if err != nil {
+		return 0, fmt.Errorf("open migrations: %w", err)
+	}
+	defer src.Close()
+
+	versions, err := availableVersions(src)
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+func availableVersions(src source.Driver) ([]uint, error) {
+	version, err := src.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	versions := []uint{version}
+	for {
+		version, err = src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
+
+// MigrationVersion reads the version recorded by golang-migrate without
+// opening a migrate instance, which would hold a connection of the pool.
+func MigrationVersion(ctx context.Context, db *sqlx.DB) (uint, bool, error) {
+	var state struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	err := db.GetContext(ctx, &state, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if err != nil {
+		return 0, false, err
+	}
+	return state.Version, state.Dirty, nil
+}
@@ -86,7 +86,7 @@ func WaitForServiceReady(t *testing.T) {
 	t.Log("Waiting for service to be ready...")
 
 	WaitFor(t, healthTimeout, func() bool {
-		resp, err := http.Get(serviceUtilHost + "/ready")
+		resp, err := http.Get(serviceUtilHost + "/health/ready")
 		if err != nil {
 			return false
 		}
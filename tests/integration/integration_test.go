@@ -1,10 +1,16 @@
 package integration
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Sugyk/avito_test_task/internal/models"
+	"github.com/Sugyk/avito_test_task/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -330,3 +336,508 @@ func TestUsersGetReview(t *testing.T) {
 	assert.Equal(t, expectedResp.PullRequests[0].PullRequestId, "TestUsersGetReview1")
 	assert.Equal(t, expectedResp.PullRequests[1].PullRequestId, "TestUsersGetReview2")
 }
+
+func TestUnavailableUsersAreNotReviewers(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestUnavailable",
+		Members: []models.TeamMember{
+			{UserId: "TestUnavailable1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestUnavailable2", Username: "Away", IsActive: bool_pointer(true)},
+			{UserId: "TestUnavailable3", Username: "Present", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	now := time.Now()
+	startsAt, endsAt := now.Add(-time.Hour), now.Add(24*time.Hour)
+	periodResp := models.UnavailabilityResponse{}
+	resp, body := DoPOST(t, "/users/availability", models.UnavailabilityCreateRequest{
+		UserId:   "TestUnavailable2",
+		StartsAt: &startsAt,
+		EndsAt:   &endsAt,
+		Reason:   "vacation",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &periodResp)
+
+	prResp := models.PullRequestCreateResponse201{}
+	resp, body = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestUnavailable1",
+		PullRequestName: "UnavailableTest",
+		AuthorId:        "TestUnavailable1",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &prResp)
+	assert.Equal(t, []string{"TestUnavailable3"}, prResp.Pr.AssignedReviewers)
+
+	// the manual flag is untouched
+	teamResp := models.Team{}
+	resp, body = DoGET(t, "/team/get?team_name=TestUnavailable", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &teamResp)
+	for _, member := range teamResp.Members {
+		assert.True(t, *member.IsActive, member.UserId)
+	}
+
+	resp, _ = DoDELETE(t, "/users/availability/"+strconv.FormatInt(periodResp.Period.Id, 10), nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	resp, body = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestUnavailable2",
+		PullRequestName: "UnavailableTest",
+		AuthorId:        "TestUnavailable1",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &prResp)
+	assert.ElementsMatch(t, []string{"TestUnavailable2", "TestUnavailable3"}, prResp.Pr.AssignedReviewers)
+}
+
+func TestImportKeepsAssignedAt(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestImportAssignedAt",
+		Members: []models.TeamMember{
+			{UserId: "TestImportAssignedAt1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestImportAssignedAt2", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestImportAssignedAt3", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestImportAssignedAt4", Username: "Member", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	db := NewTestDB(t)
+	assigned := map[string][]string{}
+	for _, id := range []string{"TestImportAssignedAtKept", "TestImportAssignedAtChanged"} {
+		resp, _ = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+			PullRequestId:   id,
+			PullRequestName: "ImportTest",
+			AuthorId:        "TestImportAssignedAt1",
+		}, nil)
+		AssertStatusCode(t, resp, http.StatusCreated)
+		reviewers := []string{}
+		assert.NoError(t, db.Select(&reviewers, `SELECT user_id FROM PullRequestsUsers WHERE pr_id = $1 ORDER BY id`, id))
+		assert.Len(t, reviewers, 2)
+		assigned[id] = reviewers
+	}
+	// the changed pull request keeps its first reviewer and gets the member
+	// who was not assigned instead of the second one
+	kept := assigned["TestImportAssignedAtKept"]
+	changed := []string{assigned["TestImportAssignedAtChanged"][0]}
+	for _, member := range addReq.Members[1:] {
+		if !slices.Contains(assigned["TestImportAssignedAtChanged"], member.UserId) {
+			changed = append(changed, member.UserId)
+		}
+	}
+
+	_, err := db.Exec(`UPDATE PullRequestsUsers SET assigned_at = now() - interval '3 hours' WHERE pr_id LIKE 'TestImportAssignedAt%'`)
+	assert.NoError(t, err)
+	var before time.Time
+	err = db.Get(&before, `SELECT assigned_at FROM PullRequestsUsers WHERE pr_id = 'TestImportAssignedAtKept' AND user_id = $1`, kept[0])
+	assert.NoError(t, err)
+
+	repo := repository.NewRepository(db, slog.Default())
+	err = repo.ImportDump(context.Background(), &models.Dump{
+		Teams: []models.Team{addReq},
+		PullRequests: []models.PullRequest{
+			{
+				PullRequestId:     "TestImportAssignedAtKept",
+				PullRequestName:   "ImportTest",
+				AuthorId:          "TestImportAssignedAt1",
+				Status:            models.StatusOpen,
+				AssignedReviewers: kept,
+			},
+			{
+				PullRequestId:     "TestImportAssignedAtChanged",
+				PullRequestName:   "ImportTest",
+				AuthorId:          "TestImportAssignedAt1",
+				Status:            models.StatusOpen,
+				AssignedReviewers: changed,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	var after time.Time
+	err = db.Get(&after, `SELECT assigned_at FROM PullRequestsUsers WHERE pr_id = 'TestImportAssignedAtKept' AND user_id = $1`, kept[0])
+	assert.NoError(t, err)
+	assert.True(t, before.Equal(after), "assigned_at changed from %s to %s", before, after)
+
+	reviewers := []string{}
+	err = db.Select(&reviewers, `SELECT user_id FROM PullRequestsUsers WHERE pr_id = 'TestImportAssignedAtChanged' ORDER BY id`)
+	assert.NoError(t, err)
+	assert.Equal(t, changed, reviewers)
+	var keptAt time.Time
+	err = db.Get(&keptAt, `SELECT assigned_at FROM PullRequestsUsers WHERE pr_id = 'TestImportAssignedAtChanged' AND user_id = $1`, changed[0])
+	assert.NoError(t, err)
+	assert.True(t, keptAt.Equal(before), "assigned_at of a kept reviewer changed")
+}
+
+func TestTeamSettings(t *testing.T) {
+	addTeam(t, "TestTeamSettings", "TestTeamSettings1", "Member", true)
+
+	hours := 24
+	settingsResp := models.TeamSettingsResponse200{}
+	resp, body := DoPOST(t, "/team/settings", models.TeamSettings{TeamName: "TestTeamSettings", StaleReviewHours: &hours}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &settingsResp)
+	if assert.NotNil(t, settingsResp.Settings.StaleReviewHours) {
+		assert.Equal(t, 24, *settingsResp.Settings.StaleReviewHours)
+	}
+
+	// settings left out are reset
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{TeamName: "TestTeamSettings"}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	settingsResp = models.TeamSettingsResponse200{}
+	resp, body = DoGET(t, "/team/settings?team_name=TestTeamSettings", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &settingsResp)
+	assert.Nil(t, settingsResp.Settings.StaleReviewHours)
+
+	resp, _ = DoGET(t, "/team/settings?team_name=TestTeamSettingsGhost", nil)
+	AssertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestReviewsOverdue(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestReviewsOverdue",
+		Members: []models.TeamMember{
+			{UserId: "TestReviewsOverdue1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestReviewsOverdue2", Username: "Reviewer", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	hours := 2
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{TeamName: "TestReviewsOverdue", FirstReviewSLAHours: &hours}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	resp, _ = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestReviewsOverdue1",
+		PullRequestName: "OverdueTest",
+		AuthorId:        "TestReviewsOverdue1",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	reviewResp := models.UsersGetReviewResponse200{}
+	resp, body := DoGET(t, "/users/getReview?user_id=TestReviewsOverdue2", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &reviewResp)
+	if assert.Len(t, reviewResp.PullRequests, 1) {
+		assert.NotNil(t, reviewResp.PullRequests[0].DueAt)
+		assert.False(t, reviewResp.PullRequests[0].Overdue)
+	}
+
+	// the reviewer was assigned longer ago than the SLA
+	db := NewTestDB(t)
+	_, err := db.Exec(`UPDATE PullRequestsUsers SET assigned_at = now() - interval '3 hours' WHERE pr_id = $1`, "TestReviewsOverdue1")
+	assert.NoError(t, err)
+
+	resp, body = DoGET(t, "/users/getReview?user_id=TestReviewsOverdue2", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &reviewResp)
+	if assert.Len(t, reviewResp.PullRequests, 1) {
+		assert.True(t, reviewResp.PullRequests[0].Overdue)
+	}
+
+	overdueResp := models.OverdueReviewsResponse200{}
+	resp, body = DoGET(t, "/reviews/overdue?team_name=TestReviewsOverdue", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &overdueResp)
+	if assert.Len(t, overdueResp.Reviews, 1) {
+		assert.Equal(t, "TestReviewsOverdue2", overdueResp.Reviews[0].ReviewerId)
+		assert.GreaterOrEqual(t, overdueResp.Reviews[0].AgeSeconds, int64(3*60*60))
+	}
+}
+
+func TestExpertsArePreferred(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestExperts",
+		Members: []models.TeamMember{
+			{UserId: "TestExperts1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestExperts2", Username: "Novice", IsActive: bool_pointer(true)},
+			{UserId: "TestExperts3", Username: "Novice", IsActive: bool_pointer(true)},
+			{UserId: "TestExperts4", Username: "SQL expert", IsActive: bool_pointer(true)},
+			{UserId: "TestExperts5", Username: "API owner", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	resp, _ = DoPOST(t, "/users/expertise", models.UserExpertise{UserId: "TestExperts4", Tags: []string{"SQL"}}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	resp, _ = DoPOST(t, "/team/pathRules", models.TeamPathRules{
+		TeamName: "TestExperts",
+		Rules: []models.PathRule{
+			{Pattern: "*.sql", Tags: []string{"sql"}},
+			{Pattern: "/internal/api/", Users: []string{"TestExperts5"}},
+		},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	rulesResp := models.TeamPathRulesResponse200{}
+	resp, body := DoGET(t, "/team/pathRules?team_name=TestExperts", nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &rulesResp)
+	assert.Len(t, rulesResp.PathRules.Rules, 2)
+
+	prResp := models.PullRequestCreateResponse201{}
+	resp, body = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestExperts1",
+		PullRequestName: "ExpertsTest",
+		AuthorId:        "TestExperts1",
+		Files:           []string{"internal/migrations/1.up.sql", "internal/api/server.go"},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &prResp)
+	assert.ElementsMatch(t, []string{"TestExperts4", "TestExperts5"}, prResp.Pr.AssignedReviewers)
+
+	resp, body = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestExperts2",
+		PullRequestName: "ExpertsTest",
+		AuthorId:        "TestExperts1",
+		Labels:          []string{"sql"},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &prResp)
+	assert.Contains(t, prResp.Pr.AssignedReviewers, "TestExperts4")
+}
+
+func TestCodeownersAreAssigned(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestCodeowners",
+		Members: []models.TeamMember{
+			{UserId: "TestCodeowners1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestCodeowners2", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCodeowners3", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCodeowners4", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCodeowners5", Username: "Owner", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	codeownersResp := models.TeamCodeownersResponse200{}
+	resp, body := DoPOST(t, "/team/codeowners", models.TeamCodeownersRequest{
+		TeamName: "TestCodeowners",
+		Content:  "# owners\n*.sql @TestCodeowners5 dba@example.com\n",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &codeownersResp)
+	assert.Equal(t, []string{"dba@example.com"}, codeownersResp.Codeowners.IgnoredOwners)
+
+	for i := range 3 {
+		prResp := models.PullRequestCreateResponse201{}
+		resp, body = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+			PullRequestId:   "TestCodeowners" + strconv.Itoa(i),
+			PullRequestName: "CodeownersTest",
+			AuthorId:        "TestCodeowners1",
+			Files:           []string{"migrations/1.up.sql"},
+		}, nil)
+		AssertStatusCode(t, resp, http.StatusCreated)
+		UnmarshalJSON(t, body, &prResp)
+		assert.Len(t, prResp.Pr.AssignedReviewers, 2)
+		assert.Contains(t, prResp.Pr.AssignedReviewers, "TestCodeowners5")
+	}
+
+	resp, _ = DoPOST(t, "/team/codeowners", models.TeamCodeownersRequest{TeamName: "TestCodeowners", Content: "!*.sql @x\n"}, nil)
+	AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func TestRequiredAndExcludedReviewers(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestRequired",
+		Members: []models.TeamMember{
+			{UserId: "TestRequired1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestRequired2", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestRequired3", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestRequired4", Username: "Required", IsActive: bool_pointer(true)},
+			{UserId: "TestRequired5", Username: "Inactive", IsActive: bool_pointer(false)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	for i := range 3 {
+		prResp := models.PullRequestCreateResponse201{}
+		resp, body := DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+			PullRequestId:     "TestRequired" + strconv.Itoa(i),
+			PullRequestName:   "RequiredTest",
+			AuthorId:          "TestRequired1",
+			RequiredReviewers: []string{"TestRequired4"},
+			ExcludedReviewers: []string{"TestRequired2"},
+		}, nil)
+		AssertStatusCode(t, resp, http.StatusCreated)
+		UnmarshalJSON(t, body, &prResp)
+		assert.Equal(t, []string{"TestRequired4", "TestRequired3"}, prResp.Pr.AssignedReviewers)
+	}
+
+	// the exclusion outlives the creation
+	resp, _ = DoPOST(t, "/pullRequest/reassign", models.PullRequestReassignRequest{
+		PullRequestId: "TestRequired0",
+		OldReviewerId: "TestRequired3",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusConflict)
+	resp, body := DoPOST(t, "/pullRequest/addReviewer", models.PullRequestReviewerRequest{
+		PullRequestId: "TestRequired0",
+		ReviewerId:    "TestRequired2",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusConflict)
+	assert.Contains(t, string(body), models.ErrReviewerExcluded.Error())
+
+	resp, _ = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:     "TestRequiredInactive",
+		PullRequestName:   "RequiredTest",
+		AuthorId:          "TestRequired1",
+		RequiredReviewers: []string{"TestRequired5"},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusBadRequest)
+
+	resp, _ = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:     "TestRequiredUnknown",
+		PullRequestName:   "RequiredTest",
+		AuthorId:          "TestRequired1",
+		RequiredReviewers: []string{"TestRequiredGhost"},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestAddAndRemoveReviewer(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestAddReviewer",
+		Members: []models.TeamMember{
+			{UserId: "TestAddReviewer1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestAddReviewer2", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestAddReviewer3", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestAddReviewer4", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestAddReviewer5", Username: "Inactive", IsActive: bool_pointer(false)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	minReviewers, maxReviewers := 1, 3
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{
+		TeamName:     "TestAddReviewer",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	resp, _ = DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:     "TestAddReviewer",
+		PullRequestName:   "AddReviewerTest",
+		AuthorId:          "TestAddReviewer1",
+		RequiredReviewers: []string{"TestAddReviewer2", "TestAddReviewer3"},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	change := func(path, reviewerID string) (*http.Response, []byte) {
+		return DoPOST(t, path, models.PullRequestReviewerRequest{PullRequestId: "TestAddReviewer", ReviewerId: reviewerID}, nil)
+	}
+
+	reviewerResp := models.PullRequestReviewerResponse200{}
+	resp, body := change("/pullRequest/addReviewer", "TestAddReviewer4")
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &reviewerResp)
+	assert.Equal(t, []string{"TestAddReviewer2", "TestAddReviewer3", "TestAddReviewer4"}, reviewerResp.Pr.AssignedReviewers)
+
+	resp, _ = change("/pullRequest/addReviewer", "TestAddReviewer4")
+	AssertStatusCode(t, resp, http.StatusConflict)
+	resp, _ = change("/pullRequest/addReviewer", "TestAddReviewer1")
+	AssertStatusCode(t, resp, http.StatusConflict)
+	resp, _ = change("/pullRequest/addReviewer", "TestAddReviewer5")
+	AssertStatusCode(t, resp, http.StatusConflict)
+
+	resp, _ = change("/pullRequest/removeReviewer", "TestAddReviewer2")
+	AssertStatusCode(t, resp, http.StatusOK)
+	reviewerResp = models.PullRequestReviewerResponse200{}
+	resp, body = change("/pullRequest/removeReviewer", "TestAddReviewer3")
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &reviewerResp)
+	assert.Equal(t, []string{"TestAddReviewer4"}, reviewerResp.Pr.AssignedReviewers)
+
+	// min_reviewers is 1
+	resp, _ = change("/pullRequest/removeReviewer", "TestAddReviewer4")
+	AssertStatusCode(t, resp, http.StatusConflict)
+	resp, _ = change("/pullRequest/removeReviewer", "TestAddReviewer2")
+	AssertStatusCode(t, resp, http.StatusConflict)
+
+	resp, _ = DoPOST(t, "/pullRequest/merge", models.PullRequestMergeRequest{PullRequestId: "TestAddReviewer"}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	resp, _ = change("/pullRequest/addReviewer", "TestAddReviewer2")
+	AssertStatusCode(t, resp, http.StatusConflict)
+}
+
+func TestCreateUsesReviewerLimits(t *testing.T) {
+	addReq := models.Team{
+		TeamName: "TestCreateLimits",
+		Members: []models.TeamMember{
+			{UserId: "TestCreateLimits1", Username: "Author", IsActive: bool_pointer(true)},
+			{UserId: "TestCreateLimits2", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCreateLimits3", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCreateLimits4", Username: "Member", IsActive: bool_pointer(true)},
+			{UserId: "TestCreateLimits5", Username: "Member", IsActive: bool_pointer(true)},
+		},
+	}
+	resp, _ := DoPOST(t, "/team/add", addReq, nil)
+	defer resp.Body.Close()
+	AssertStatusCode(t, resp, http.StatusCreated)
+
+	minReviewers, maxReviewers := 3, 4
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{
+		TeamName:     "TestCreateLimits",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	prResp := models.PullRequestCreateResponse201{}
+	resp, body := DoPOST(t, "/pullRequest/create", models.PullRequestCreateRequest{
+		PullRequestId:   "TestCreateLimits",
+		PullRequestName: "CreateLimitsTest",
+		AuthorId:        "TestCreateLimits1",
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusCreated)
+	UnmarshalJSON(t, body, &prResp)
+	assert.Len(t, prResp.Pr.AssignedReviewers, 3)
+
+	maxReviewers = 1
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{
+		TeamName:     "TestCreateLimits",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusBadRequest)
+	minReviewers = 0
+	resp, _ = DoPOST(t, "/team/settings", models.TeamSettings{
+		TeamName:     "TestCreateLimits",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+
+	bulkResp := models.PullRequestBulkCreateResponse200{}
+	resp, body = DoPOST(t, "/pullRequest/bulkCreate", models.PullRequestBulkCreateRequest{
+		PullRequests: []models.PullRequestCreateRequest{
+			{PullRequestId: "TestCreateLimitsBulk1", PullRequestName: "one", AuthorId: "TestCreateLimits1"},
+			// required reviewers are assigned even above max_reviewers
+			{PullRequestId: "TestCreateLimitsBulk2", PullRequestName: "two", AuthorId: "TestCreateLimits1",
+				RequiredReviewers: []string{"TestCreateLimits2", "TestCreateLimits3"}},
+		},
+	}, nil)
+	AssertStatusCode(t, resp, http.StatusOK)
+	UnmarshalJSON(t, body, &bulkResp)
+	if assert.Equal(t, 2, bulkResp.Created) {
+		assert.Len(t, bulkResp.Results[0].Pr.AssignedReviewers, 1)
+		assert.Equal(t, []string{"TestCreateLimits2", "TestCreateLimits3"}, bulkResp.Results[1].Pr.AssignedReviewers)
+	}
+}